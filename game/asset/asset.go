@@ -1,81 +1,267 @@
 package asset
 
 import (
-	"path/filepath"
-	// For decoding png assets
-	// _ "image/png"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	// Register the png format for Img.
+	_ "image/png"
+	"path"
+	"sync"
+
+	"github.com/Bredgren/game1/game/sprite"
+	"github.com/Bredgren/game1/game/ui"
+	"github.com/golang/freetype/truetype"
+	"github.com/hajimehoshi/ebiten"
+	"golang.org/x/image/font"
 )
 
-// var img = map[string]*ebiten.Image{}
+// root is the directory that the embedded assets are under.
+const root = "assets"
+
+var (
+	// fsys is the FS that assets are loaded from.
+	fsys FS = Embedded()
+	// cache holds loaded assets by path until they are unloaded. cacheMu guards it since
+	// assets may be loaded by a Batch in the background.
+	cache   = map[string]interface{}{}
+	cacheMu sync.Mutex
+)
 
-// var sheetDesc = map[string]sprite.SheetDesc{}
+// SetFS changes where assets are loaded from. Everything that was loaded from the previous
+// FS is unloaded.
+func SetFS(f FS) {
+	UnloadAll()
+	fsys = f
+}
 
-const root = "assets"
+// ReadFile returns the contents of the file at path, e.g. "psd/test.psd". The result is not
+// cached.
+func ReadFile(path string) ([]byte, error) {
+	return fsys.ReadFile(path)
+}
+
+// Psd loads the Descs in "psd/<name>.psd". See sprite.Psd for the format.
+func Psd(name string) ([]sprite.Desc, error) {
+	p := psdPath(name)
+	if descs, ok := cached(p).([]sprite.Desc); ok {
+		return descs, nil
+	}
+	data, err := fsys.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("load psd '%s': %v", name, err)
+	}
+	descs, err := sprite.Psd(data)
+	if err != nil {
+		return nil, fmt.Errorf("load psd '%s': %v", name, err)
+	}
+	store(p, descs)
+	return descs, nil
+}
+
+// Sheet loads the Descs in the sprite sheet "sheet/<name>.png" and its JSON description
+// "sheet/<name>.json". See sprite.Sheet for the format of the description.
+func Sheet(name string) ([]sprite.Desc, error) {
+	pngPath, descPath := sheetPaths(name)
+	if descs, ok := cached(pngPath).([]sprite.Desc); ok {
+		return descs, nil
+	}
+	png, err := fsys.ReadFile(pngPath)
+	if err != nil {
+		return nil, fmt.Errorf("load sheet '%s': %v", name, err)
+	}
+	desc, err := fsys.ReadFile(descPath)
+	if err != nil {
+		return nil, fmt.Errorf("load sheet '%s': %v", name, err)
+	}
+	descs, err := sprite.Sheet(png, desc)
+	if err != nil {
+		return nil, fmt.Errorf("load sheet '%s': %v", name, err)
+	}
+	store(pngPath, descs)
+	return descs, nil
+}
+
+// Img loads the image "img/<name>.png".
+func Img(name string) (*ebiten.Image, error) {
+	p := imgPath(name)
+	if img, ok := cached(p).(*ebiten.Image); ok {
+		return img, nil
+	}
+	data, err := fsys.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("load img '%s': %v", name, err)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("load img '%s': %v", name, err)
+	}
+	img, err := ebiten.NewImageFromImage(src, ebiten.FilterNearest)
+	if err != nil {
+		return nil, fmt.Errorf("load img '%s': %v", name, err)
+	}
+	store(p, img)
+	return img, nil
+}
+
+// Audio loads the encoded contents of "audio/<filename>", e.g. "audio/jump.wav". Decoding
+// is left to the caller since it depends on the format and the audio context.
+func Audio(filename string) ([]byte, error) {
+	p := path.Join("audio", filename)
+	if data, ok := cached(p).([]byte); ok {
+		return data, nil
+	}
+	data, err := fsys.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("load audio '%s': %v", filename, err)
+	}
+	store(p, data)
+	return data, nil
+}
+
+// Level decodes the JSON level "level/<name>.json" into v. Levels aren't cached since
+// they are decoded into the caller's value.
+func Level(name string, v interface{}) error {
+	data, err := fsys.ReadFile(levelPath(name))
+	if err != nil {
+		return fmt.Errorf("load level '%s': %v", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("load level '%s': %v", name, err)
+	}
+	return nil
+}
+
+// UI loads the ui definition "ui/<name>.json". See ui.Def for the format.
+func UI(name string) (*ui.Def, error) {
+	p := uiPath(name)
+	if def, ok := cached(p).(*ui.Def); ok {
+		return def, nil
+	}
+	data, err := fsys.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("load ui '%s': %v", name, err)
+	}
+	def, err := ui.ParseDef(data)
+	if err != nil {
+		return nil, fmt.Errorf("load ui '%s': %v", name, err)
+	}
+	store(p, def)
+	return def, nil
+}
+
+// Theme loads the ui theme "theme/<name>.json". See ui.Theme for the format.
+func Theme(name string) (*ui.Theme, error) {
+	p := themePath(name)
+	if theme, ok := cached(p).(*ui.Theme); ok {
+		return theme, nil
+	}
+	data, err := fsys.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("load theme '%s': %v", name, err)
+	}
+	theme, err := ui.ParseTheme(data)
+	if err != nil {
+		return nil, fmt.Errorf("load theme '%s': %v", name, err)
+	}
+	store(p, theme)
+	return theme, nil
+}
+
+// Unload removes the asset at path, e.g. "psd/test.psd", from the cache and frees any
+// resources it owns. Anything still using the asset must not be drawn afterwards. Sheets
+// are cached by the path of their png image. Unloading a font also unloads its faces.
+func Unload(path string) {
+	cacheMu.Lock()
+	a, ok := cache[path]
+	delete(cache, path)
+	cacheMu.Unlock()
+	if !ok {
+		return
+	}
+	switch a := a.(type) {
+	case []sprite.Desc:
+		disposeDescs(a)
+	case *ebiten.Image:
+		a.Dispose()
+	case *truetype.Font:
+		unloadFaces(path)
+	case font.Face:
+		a.Close()
+	}
+}
 
-func init() {
-	// initImg()
-	// initSheetDesc()
-}
-
-// Psd returns the contents of the given PSD file.
-func Psd(name string) []byte {
-	return MustAsset(filepath.Join(root, "psd", name+".psd"))
-}
-
-// func initImg() {
-// 	names, err := AssetDir(filepath.Join(root, "img"))
-// 	if err != nil {
-// 		log.Fatalf("Reading image assets: %s", err)
-// 	}
-// 	for _, imgName := range names {
-// 		r := bytes.NewReader(MustAsset(filepath.Join(root, "img", imgName)))
-// 		i, _, err := image.Decode(r)
-// 		if err != nil {
-// 			log.Fatalf("Loading image %s: %s", imgName, err)
-// 		}
-//
-// 		extension := filepath.Ext(imgName)
-// 		name := imgName[0 : len(imgName)-len(extension)]
-// 		img[name], err = ebiten.NewImageFromImage(i, ebiten.FilterNearest)
-// 		if err != nil {
-// 			log.Fatalf("Converting image %s: %s", imgName, err)
-// 		}
-// 	}
-// }
-//
-// // Img retrieves an image by filename (minus extension).
-// func Img(name string) *ebiten.Image {
-// 	i, ok := img[name]
-// 	if !ok {
-// 		log.Fatalf("No image %s", name)
-// 	}
-// 	return i
-// }
-
-// func initSheetDesc() {
-// 	names, err := AssetDir(filepath.Join(root, "sheetDesc"))
-// 	if err != nil {
-// 		log.Fatalf("Reading sheet desc assets: %s", err)
-// 	}
-// 	for _, descName := range names {
-// 		r := MustAsset(filepath.Join(root, "sheetDesc", descName))
-// 		var s sprite.SheetDesc
-// 		err := json.Unmarshal(r, &s)
-// 		if err != nil {
-// 			log.Fatalf("Loading sheet %s: %s", descName, err)
-// 		}
-//
-// 		extension := filepath.Ext(descName)
-// 		name := descName[0 : len(descName)-len(extension)]
-// 		sheetDesc[name] = s
-// 	}
-// }
-
-// // SheetDesc retrieves an a sprite sheet description by filename (minus extension).
-// func SheetDesc(name string) sprite.SheetDesc {
-// 	s, ok := sheetDesc[name]
-// 	if !ok {
-// 		log.Fatalf("No sheetDesc %s", name)
-// 	}
-// 	return s
-// }
+// UnloadAll unloads every cached asset.
+func UnloadAll() {
+	cacheMu.Lock()
+	paths := make([]string, 0, len(cache))
+	for p := range cache {
+		paths = append(paths, p)
+	}
+	cacheMu.Unlock()
+	for _, p := range paths {
+		Unload(p)
+	}
+}
+
+// forget removes the asset at path from the cache without freeing it, so that it is
+// loaded again next time while the old copy can still be used.
+func forget(path string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	delete(cache, path)
+}
+
+func cached(path string) interface{} {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return cache[path]
+}
+
+func store(path string, a interface{}) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache[path] = a
+}
+
+// disposeDescs disposes of the atlas images shared by descs.
+func disposeDescs(descs []sprite.Desc) {
+	disposed := map[*ebiten.Image]bool{}
+	for _, d := range descs {
+		for _, f := range d.Frames {
+			if f.Img != nil && !disposed[f.Img] {
+				disposed[f.Img] = true
+				f.Img.Dispose()
+			}
+		}
+	}
+}
+
+func psdPath(name string) string {
+	return path.Join("psd", name+".psd")
+}
+
+func sheetPaths(name string) (png, desc string) {
+	return path.Join("sheet", name+".png"), path.Join("sheet", name+".json")
+}
+
+func imgPath(name string) string {
+	return path.Join("img", name+".png")
+}
+
+func fontPath(name string) string {
+	return path.Join("font", name+".ttf")
+}
+
+func uiPath(name string) string {
+	return path.Join("ui", name+".json")
+}
+
+func themePath(name string) string {
+	return path.Join("theme", name+".json")
+}
+
+func levelPath(name string) string {
+	return path.Join("level", name+".json")
+}
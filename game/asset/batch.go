@@ -0,0 +1,98 @@
+package asset
+
+import (
+	"sync"
+
+	"github.com/Bredgren/game1/game/util"
+)
+
+// Batch loads a group of assets in the background and reports its progress. Jobs
+// usually call the loaders in this package so that their results are cached, then the
+// game calls the loaders again from its own goroutine once the Batch is done, e.g.
+//  b.Add("psd test", func() error {
+//  	_, err := Psd("test")
+//  	return err
+//  })
+type Batch struct {
+	jobs     []batchJob
+	mu       sync.Mutex
+	started  bool
+	finished int
+	errs     util.ErrorList
+}
+
+type batchJob struct {
+	name string
+	load func() error
+}
+
+// Add adds a job to the Batch. The name is only used in errors. Adding jobs after Start
+// has no effect.
+func (b *Batch) Add(name string, load func() error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started {
+		return
+	}
+	b.jobs = append(b.jobs, batchJob{name: name, load: load})
+}
+
+// Start runs the jobs, in the order they were added, on a new goroutine. A failed job
+// doesn't stop the rest from running. Start only has an effect the first time it's called.
+func (b *Batch) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started {
+		return
+	}
+	b.started = true
+	go b.run(b.jobs)
+}
+
+func (b *Batch) run(jobs []batchJob) {
+	for _, j := range jobs {
+		err := j.load()
+		b.mu.Lock()
+		if err != nil {
+			b.errs = append(b.errs, &LoadError{Name: j.name, Err: err})
+		}
+		b.finished++
+		b.mu.Unlock()
+	}
+}
+
+// Progress returns the number of jobs that have finished and the total number of jobs.
+func (b *Batch) Progress() (finished, total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.finished, len(b.jobs)
+}
+
+// Done returns true once every job has finished.
+func (b *Batch) Done() bool {
+	finished, total := b.Progress()
+	return b.started && finished == total
+}
+
+// Err returns the errors of the jobs that have failed so far as a util.ErrorList, or nil
+// if there are none.
+func (b *Batch) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.errs) == 0 {
+		return nil
+	}
+	errs := make(util.ErrorList, len(b.errs))
+	copy(errs, b.errs)
+	return errs
+}
+
+// LoadError is the error of a Batch job.
+type LoadError struct {
+	Name string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
@@ -1,6 +1,16 @@
 // Code generated by go-bindata.
 // sources:
 // assets/psd/test.psd
+// assets/theme/default.json
+// assets/theme/highcontrast.json
+// assets/ui/axismenu.json
+// assets/ui/mainmenu.json
+// assets/ui/pausemenu.json
+// assets/ui/settings/audio.json
+// assets/ui/settings/controls.json
+// assets/ui/settings/gameplay.json
+// assets/ui/settings/video.json
+// assets/ui/settingsmenu.json
 // DO NOT EDIT!
 
 package asset
@@ -88,6 +98,206 @@ func assetsPsdTestPsd() (*asset, error) {
 	return a, nil
 }
 
+var _assetsUiMainmenuJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x8e\xbb\x0e\xc2\x30\x0c\x45\xf7\x7c\x45\xe4\x99\x85\x95\x91\x7e\x02\x23\x62\x08\x91\x55\xa2\xba\x49\x95\x98\x47\x55\xf5\xdf\xb1\xd3\x20\x3a\xd9\xf7\xa1\xa3\xbb\x18\x6b\x21\xa7\xc4\x70\xb2\x8b\xfc\xa2\x78\x9e\x50\x14\xbc\x30\x73\xf0\x8e\xe0\xb0\xf9\x6f\xed\x1c\x9b\xe8\xdd\xb4\x53\x48\x38\x62\xe4\x22\xd6\xb5\x3a\xb6\xc1\x6a\x5a\x78\xa6\x4a\x2c\xa3\x23\x3a\x3f\x99\x53\x6c\xd0\x9a\xa7\xd8\x51\xf0\x43\x6d\x20\x73\x88\x7d\xd9\xc7\x0d\xae\x03\xff\x28\x72\x77\x94\x65\x16\x5c\xf4\x8f\x94\xd5\xf2\x52\xc2\xac\x1e\xe3\x47\xeb\x70\xf9\xd1\xd6\x46\xdb\xee\xcd\xe8\xb7\x9a\x2f\x1b\x29\x6d\x91\xfc\x00\x00\x00")
+
+func assetsUiMainmenuJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiMainmenuJson,
+		"assets/ui/mainmenu.json",
+	)
+}
+
+func assetsUiMainmenuJson() (*asset, error) {
+	bytes, err := assetsUiMainmenuJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/mainmenu.json", size: 252, mode: os.FileMode(420), modTime: time.Unix(1792429224, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiAxismenuJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x52\xbb\x6e\xc3\x30\x0c\xdc\xfd\x15\x86\xe6\xa0\x48\x8b\x76\xc9\xd4\xc7\x27\x04\xe8\x52\x64\x50\x64\xc6\x16\x2a\x4b\x86\x44\xb7\x49\x0d\xfd\x7b\x29\xc5\x16\xe8\x0c\x45\xbd\xc8\x3c\x92\xc7\x3b\x4a\x53\x55\xd7\xc2\x3b\x87\x62\x57\x4f\xf4\x4f\x11\x5e\x06\xa0\x48\x7c\x81\x47\xad\xa4\x11\x9b\x2b\xfe\x9d\x6a\xee\xe7\xa0\x95\x03\x8b\xc0\x40\x0f\x16\x03\x41\x1f\x19\xa9\x67\xb2\x9c\x0d\x78\x31\x99\x71\x90\x16\x16\xba\x9c\x39\x4a\xf5\xd9\x7a\x37\xda\x26\x94\xf9\x73\x4a\x37\xb9\x67\x2a\x72\x74\x2f\x5b\x10\x9b\x5a\x28\x67\x9c\x4f\xc8\xf3\xcc\x47\xd2\x74\x83\x5d\xd2\xb3\xdd\x52\xd4\x81\x6e\xbb\x2c\xf6\x91\xa2\xde\x35\xb9\x3f\xa0\x07\x54\x9d\x88\x65\x4a\x64\x4a\x66\x07\x79\x62\xd1\x6b\xe4\xf1\xca\x2f\xad\xea\xae\x33\x15\x15\x81\x4f\x18\xc2\x39\x95\x8b\x3d\xb5\x2a\xac\x5f\xce\x3a\x14\xee\xc2\xcc\xb6\xb0\xf8\xf0\x30\x80\x44\xbe\x05\x8d\xd0\x27\xff\x42\x9e\x21\xf0\x04\xe1\x83\x91\x08\xb7\xbb\x29\x02\x25\x0d\x7d\x1d\x11\x9d\x65\x6d\x54\xe0\xec\x9b\xd1\xea\x33\xdb\xce\xf2\x92\xba\xdd\x94\x06\xc5\x75\x25\xf3\xcd\x60\x26\x97\x7c\xeb\x1f\x67\x51\x9a\x55\xe3\xcd\x7b\xb8\xe5\xe3\x2f\x61\xf9\xfe\x5c\x2c\x25\xbd\x4a\x4d\x74\x81\xdb\xbb\xa7\x03\xe5\x9a\x80\x6b\xc0\x9d\x4e\x01\x32\xf6\x40\xd8\x21\xb2\x4b\x48\xfe\xea\xd9\x5f\xdc\xfc\x63\x70\x6a\xdc\xbb\xd1\xab\xb2\xc7\x77\x69\x46\x58\x76\x14\x57\x14\x07\x16\xb1\xe7\x53\xf1\x33\xd5\xc4\x2a\x56\xbf\xa9\x6b\x54\x23\x51\x03\x00\x00")
+
+func assetsUiAxismenuJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiAxismenuJson,
+		"assets/ui/axismenu.json",
+	)
+}
+
+func assetsUiAxismenuJson() (*asset, error) {
+	bytes, err := assetsUiAxismenuJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/axismenu.json", size: 849, mode: os.FileMode(420), modTime: time.Unix(1792428657, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiPausemenuJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x90\x3d\x6e\x03\x21\x10\x85\xfb\x3d\x05\xa2\x76\xe3\x26\x45\x2a\x2b\xb9\x80\x95\x94\x51\x0a\x0c\x23\x40\xe6\x67\xc5\xcc\xda\xb1\x56\x7b\xf7\x00\x8b\xd1\x16\x2e\xb7\x62\xde\x9b\xe1\xcd\x07\xf3\xc0\x18\x4f\x31\x12\x7f\x67\x73\xae\xb3\xa2\xc7\x08\x59\xf1\x1b\x24\xb2\x52\x38\x7e\x58\xfd\x7b\x99\x39\x36\xa1\xc5\xb8\x51\xe0\xc0\x43\x20\xcc\xd6\x4f\x75\x58\x0b\xab\x5d\xa4\x87\xab\x89\xa3\x08\xf0\x8c\xab\x9d\x8b\x90\x57\x9d\xe2\x14\x14\xf6\xfd\xad\x65\x55\xbd\x33\x77\x1c\xeb\x85\x06\x7e\x60\x5c\x46\x17\x53\x71\x4e\x2d\x2f\xa3\x59\x45\xa6\xf0\x1c\xdf\xb2\x32\x60\xb5\xa9\xb0\x45\xf9\xa8\xea\x7d\xa4\x04\x24\x0d\x5f\xfa\x96\x65\x43\xd2\x5e\x50\x37\x76\x5e\x27\x2e\x6b\xbe\x08\xd2\xac\x3b\x65\x1e\x82\x54\x3c\x82\xbf\x32\xce\xcf\x62\x42\x50\x3d\xb6\x87\xbe\xfa\x00\xf4\xc2\xb9\x8f\x89\x28\x86\xed\x37\xc4\xf0\xe9\xac\xbc\x96\x89\x04\x38\x79\xe0\xbb\x90\x7d\xad\x59\x3b\x91\x21\x10\xd9\xa0\x71\x1f\xb6\xef\x67\x5a\xa7\xab\xe7\xef\x50\xaa\x65\xf8\x07\x97\xab\x2a\x09\x97\x02\x00\x00")
+
+func assetsUiPausemenuJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiPausemenuJson,
+		"assets/ui/pausemenu.json",
+	)
+}
+
+func assetsUiPausemenuJson() (*asset, error) {
+	bytes, err := assetsUiPausemenuJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/pausemenu.json", size: 663, mode: os.FileMode(420), modTime: time.Unix(1792429224, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiSettingsmenuJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x93\xbd\x6e\x84\x30\x10\x84\x7b\x9e\xc2\x72\x7d\xcd\x49\xa9\xae\xcb\x51\xe4\x01\x22\xa5\x89\x52\x2c\x66\x45\xac\x18\x16\x19\x73\x09\x41\xbc\x7b\xd6\x98\xe3\xe7\x38\x9a\x88\xab\xec\x1d\x46\x33\xfe\x56\xa2\x8d\x84\x90\x96\xc8\xc9\x93\x68\xf9\xce\x93\x6b\x4a\xe4\x49\x5e\xd0\x3a\xad\xc0\xc8\x43\xd0\xbf\xbd\xe7\x38\x0c\x19\x94\x3c\x3d\x0d\x13\x1a\xcc\xb1\x70\x15\x4b\xef\xbd\x22\x86\xb0\x45\xe0\x27\x59\xfd\x4b\x85\x1b\x23\x67\x49\xc7\x99\x72\x27\x6d\x99\xd8\xbb\x2a\xd7\x98\x3e\xd6\x41\x72\xae\x9d\xa3\x62\x96\xda\x3b\xa8\x88\x8d\x56\x5f\x83\xe7\xa4\xb8\xda\x92\xa9\x6e\x6d\x43\x9d\x5f\xc0\x14\x6a\x20\x41\x7e\xa6\x90\x50\x28\x7e\xb7\x97\x14\x9b\xd0\x7a\xcd\xe1\x8f\xb7\xcb\x78\x4c\xe4\xaf\x64\xc8\xbe\x52\x6d\xd5\xf5\x51\xb1\x57\xa6\xd6\x6e\xd6\xda\x1d\x76\xe4\xba\xe8\x14\x69\x3f\xa8\xb7\x10\xb7\x4d\x14\xfa\x1e\x86\x03\x75\xaa\x77\xc4\x79\x0e\x71\xdb\x38\xa1\xef\x61\x38\x19\xe4\x58\x1a\x68\xf6\x23\x7a\x19\x13\xb7\xa1\xc6\xd6\x05\xd7\x78\xff\x88\x6e\x48\xdb\x3b\x7f\xbd\x90\x3a\xf5\x4a\x09\x19\xca\xc9\x19\xad\x77\x51\xe5\x60\xcc\x6a\x1b\xf3\x4d\x24\xc0\xe7\xfa\x17\xff\x07\xfd\xd9\x27\x5d\x49\xc2\xe9\x69\xba\xa8\x8b\xfe\x00\x95\x55\x71\xba\xcc\x04\x00\x00")
+
+func assetsUiSettingsmenuJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiSettingsmenuJson,
+		"assets/ui/settingsmenu.json",
+	)
+}
+
+func assetsUiSettingsmenuJson() (*asset, error) {
+	bytes, err := assetsUiSettingsmenuJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/settingsmenu.json", size: 1228, mode: os.FileMode(420), modTime: time.Unix(1792429224, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiSettingsControlsJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x55\xc9\x6e\xc2\x30\x10\xbd\xf3\x15\x51\xce\xa8\xa2\xaa\xb8\x70\x6b\xa9\xd4\x43\x7b\xa8\xe8\xb1\xe2\x30\x84\x69\x88\x70\x6c\xcb\x19\xb6\xa2\xfc\x7b\x6d\x67\x71\x9c\x05\x50\xdb\x1b\xf3\xfc\xfc\x66\x7b\x38\xe7\x51\x10\x84\x4a\x08\x0a\x67\xc1\x59\xff\xd6\x11\x9d\x24\xea\x28\xdc\xa3\xa2\x24\x02\x16\x8e\x0b\xfc\x60\x38\xf7\x65\x10\x83\x6c\x44\xc8\x30\x45\x4e\x99\x86\x3e\x2d\x12\x94\x62\xf6\x34\xa3\x13\xb3\x8a\x12\x38\x56\x72\xcd\x7b\x26\xb7\x63\x31\x58\x19\x56\x10\x02\x8f\x36\x42\x19\x28\xd2\x24\x54\x06\x23\x3c\xd2\x87\xd8\xa9\xc8\x52\x15\xa6\x20\xdf\x95\x48\x25\x85\x79\x29\x9b\x8f\x2f\x54\x90\x45\x4a\x30\xaf\x84\x0d\x26\xf1\xc6\x76\xf6\x30\xed\xaf\xac\x06\x87\x67\x53\x9e\x7a\x43\xf1\x85\x9a\xa3\x69\x97\xd7\xd2\x56\x28\x11\xc8\x53\xb6\xe7\x09\x61\x9a\xd5\x5d\x3f\x46\x94\x08\x9e\x75\x69\x9a\x25\x19\x10\xb6\x4a\x6f\x4f\x62\xb5\x23\x12\xbc\x73\x5d\x53\x04\x9f\xb3\x24\xda\xd6\x99\x66\x67\x93\x39\xef\xa3\xf6\x4f\xa9\xd3\x91\xde\x62\xf2\x2d\x38\xb5\xe6\x55\xf3\x9a\xce\xea\xcf\xd0\x1d\x5f\x3d\x46\xd7\x12\xd8\x91\xbc\x55\xf6\x31\x56\x31\x70\x55\xbe\xb6\x91\x60\x42\x39\xf7\x14\xfc\xb9\x01\xab\x1e\xf3\xf1\xb5\x24\xac\x29\xef\xb4\xb6\x78\x9a\x0d\x26\xd2\x87\xff\x93\x25\x86\x14\x25\xac\x87\x33\x95\x04\x3f\x5b\x4f\xb2\x65\x07\x6b\xb3\xfc\xb8\x19\xb9\xbb\x97\xfe\x72\x83\x6e\x76\x3e\x8e\x91\xa3\x02\xd6\x75\xf2\x90\x87\x87\x5f\x92\x0b\x5e\xbc\xee\xc2\x5e\xff\x5d\x72\xde\xcd\x9e\xeb\x2c\xfa\x86\x15\x17\x43\x79\x75\x7e\xfa\xbd\xc8\x8b\x6f\x17\x7f\xa5\x4d\x0b\xe4\x37\xad\xd4\xbd\xa2\x48\x94\xf0\x78\x21\x0e\x3d\x8f\x79\x7b\x60\xd7\x87\x55\x96\x19\x3c\x23\xac\xf5\x82\xd0\xef\xb8\x71\x3f\x63\xc9\xba\xf8\x0c\xec\x81\xed\x2c\xb4\xae\xee\x68\x30\x85\xa3\x86\x26\x77\xd3\xb1\xa9\x15\xa5\x0d\x26\x53\xd7\xdc\xf2\xa6\xe6\x52\x60\xec\xa9\xfd\x3a\xfa\xaf\x62\x46\x42\xe1\x33\x7e\xc1\x8e\xd1\xdf\xbf\x67\x06\x59\x14\x9a\x41\x25\x5a\xef\x61\x54\x15\x9e\x8f\xf2\xd1\x0f\xfe\xae\xe5\xe6\xae\x07\x00\x00")
+
+func assetsUiSettingsControlsJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiSettingsControlsJson,
+		"assets/ui/settings/controls.json",
+	)
+}
+
+func assetsUiSettingsControlsJson() (*asset, error) {
+	bytes, err := assetsUiSettingsControlsJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/settings/controls.json", size: 1966, mode: os.FileMode(420), modTime: time.Unix(1792430882, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiSettingsVideoJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x51\x3d\x0f\x82\x30\x14\xdc\xf9\x15\xa4\x33\x8b\x2b\x3f\xc0\xc9\x49\x8c\x8b\x71\x28\xcd\x13\x88\xa5\x25\xf4\xf1\x15\xc2\x7f\xf7\x51\x2b\x62\x02\x83\x0e\xb2\xbd\xbb\xbe\xbb\xdc\xf5\xf5\x9e\xef\xb3\x52\x6b\x64\xa1\xdf\xd3\x4c\x08\xbb\x02\x08\xb1\x1a\x4a\xcc\x04\x97\x2c\x78\xf2\xcd\xb8\xb3\x73\x20\xe1\xc5\x0c\x81\x84\x1c\x14\x1a\xa2\x2e\x96\xf1\x9d\x99\x7d\x35\xd8\x49\xeb\x68\x00\x31\x53\xc9\x51\x37\xce\x73\x4d\x6c\x0d\xde\x3a\x2e\x30\xd3\xea\xc0\x63\xa0\x30\x14\x10\xda\x31\x0a\xdb\x57\x52\x1a\x51\x02\x28\x36\x04\xcb\x4a\x91\x82\xb8\xc7\xba\x1d\x65\x35\x97\xd5\x3c\x46\x78\x9b\xe9\x27\xf9\xd5\x4d\x93\xe3\x1f\x8a\x44\xf4\xcb\xb0\xde\xa1\x13\x34\x7c\x14\xb0\xfb\xc4\x64\x08\xb9\x99\x18\xb3\x71\x8d\x73\xd4\x29\xf1\xdb\x29\x6a\x63\xa5\x9b\xc6\x3f\xa5\x24\xfc\xe2\x0a\x68\xf7\xe7\x57\xb0\xcc\xe2\x15\xbc\x17\x1a\xbc\xc1\x7b\x00\x35\xd0\xfd\x92\x74\x03\x00\x00")
+
+func assetsUiSettingsVideoJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiSettingsVideoJson,
+		"assets/ui/settings/video.json",
+	)
+}
+
+func assetsUiSettingsVideoJson() (*asset, error) {
+	bytes, err := assetsUiSettingsVideoJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/settings/video.json", size: 884, mode: os.FileMode(420), modTime: time.Unix(1792429224, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiSettingsAudioJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x91\x3f\x0f\x82\x30\x10\xc5\x77\x3e\x45\xd3\x99\xc5\x95\xdd\x4d\x17\x07\x17\xe3\x50\xca\x81\x8d\x85\x12\x7a\xfc\x0b\xe9\x77\xb7\x94\x8a\x9a\xc0\xa2\x89\x6c\xf7\xae\xfd\xbd\xbc\x97\x1b\x02\x42\x68\xa5\x14\xd2\x88\x0c\x76\xb6\x0a\xfb\x12\xac\xa2\x0d\x54\x28\x38\x93\x34\x9c\xf6\xed\xf8\x67\xe7\x45\xc6\xca\x37\x05\x12\x72\x28\x50\xdb\xd5\xc5\x6d\x88\x37\x73\xaf\x1a\x7b\xe9\x1c\x35\x20\x8a\x22\x3b\xa9\xd6\x7b\xae\xc1\xce\xe0\xc5\x31\x8e\x42\x15\x07\x16\x83\x0d\x63\x03\x42\x37\x46\xa1\x47\xa6\x11\x2a\x72\x56\xb2\xce\x81\x9a\x70\x19\xd6\x52\x24\x50\x8d\x5c\xc3\x64\x3d\x35\x73\x44\x94\x3b\x9e\x9a\x99\xbb\xfa\x69\xb6\xfa\x47\x89\x5a\x0b\xfe\x43\x87\x11\xdf\xb8\xc2\x3e\x4d\x81\xa3\xfe\xbe\x04\x4c\x06\x9b\x5f\x02\xd7\xc3\xf3\x1b\xf0\x7b\xac\xba\x8f\xf8\x3e\x80\x3d\x02\x42\xb2\x94\x3e\x78\x2a\x13\x98\xe0\x01\x96\x3d\xf9\x77\x6b\x03\x00\x00")
+
+func assetsUiSettingsAudioJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiSettingsAudioJson,
+		"assets/ui/settings/audio.json",
+	)
+}
+
+func assetsUiSettingsAudioJson() (*asset, error) {
+	bytes, err := assetsUiSettingsAudioJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/settings/audio.json", size: 875, mode: os.FileMode(420), modTime: time.Unix(1792429224, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsUiSettingsGameplayJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x8f\x3b\x0f\x82\x30\x14\x85\x77\x7e\x45\xd3\x99\xc5\x95\x55\x13\x63\xe2\x24\xa3\x71\x28\xf5\x0a\x84\x47\x09\xbd\xbc\x42\xf8\xef\x5e\x4a\x45\x89\xb2\x38\xe8\x76\xcf\x69\xcf\x97\x73\x7a\x87\x31\x5e\x2a\x85\xdc\x63\x3d\xdd\xa4\xb0\x2b\x80\x14\xaf\xa1\xc4\x58\x8a\x94\xbb\x93\xdf\x8c\x7f\x36\x56\x84\xa2\x78\x51\x90\x42\x06\x39\x6a\xb2\xce\xc6\x61\x16\x66\x5e\x35\x76\xa9\x21\x6a\x40\x8c\xf3\xf0\xa4\x1a\xcb\x5c\x0b\x1b\xc0\x33\x27\x24\xc6\x2a\x3f\x8a\x00\xa8\x0c\x15\x84\x76\xac\xc2\xf7\x22\x03\xe6\x17\x00\x57\x3e\xb8\x9f\x93\xb2\x93\x74\x50\xa6\x16\x69\x65\x9c\x90\x42\x53\x86\xdc\x18\x21\xd3\x0b\x57\xf3\x61\x26\x5d\xec\x35\xc3\x7f\xb0\x69\x4b\x45\x4a\xc1\xfc\x48\x24\xb0\xbe\x2a\x02\x99\x04\xaa\x5d\x0c\xb3\x45\x3c\x69\x08\x16\xf0\xd7\x2d\x7e\xa4\x1a\xb6\x83\xa0\x0a\xd9\x21\xbf\xa9\xef\xe6\x68\x82\x18\xc6\x84\x78\x1f\xe4\x3c\xd4\xe0\x0c\xce\x1d\x2e\xd0\xa7\x8a\xcf\x02\x00\x00")
+
+func assetsUiSettingsGameplayJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsUiSettingsGameplayJson,
+		"assets/ui/settings/gameplay.json",
+	)
+}
+
+func assetsUiSettingsGameplayJson() (*asset, error) {
+	bytes, err := assetsUiSettingsGameplayJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/ui/settings/gameplay.json", size: 719, mode: os.FileMode(420), modTime: time.Unix(1792429224, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsThemeDefaultJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdd\x56\x4d\x8b\xdb\x30\x10\xbd\xe7\x57\x04\xf7\x6a\x96\x38\x5f\x0d\x7b\x2a\xa5\x87\x16\x7a\x28\xed\x42\x0f\x4b\x0e\xb2\x2c\xdb\x62\x65\xcb\x48\xf2\x26\xd9\xe0\xff\xde\x91\xe4\x0f\xd9\xeb\xdd\x4d\xd9\x94\x86\xd8\x84\xa0\x79\xf2\x68\xde\x9b\x19\x49\xc7\xc9\x74\xea\xe5\x28\x23\xde\xed\xd4\xfb\x42\x62\x54\x32\xe5\xf9\xda\x88\x39\xe3\x42\x82\xf9\x08\x23\x18\x2b\xb2\x57\x7a\xd2\x87\x99\x79\xcc\x24\x30\xa7\x34\x49\x19\xfc\x2c\x16\x9b\xa7\xc3\xf2\x31\x73\x99\x87\xbc\xcc\x23\x83\xe0\x8d\x76\x86\x37\x1d\x86\x1e\x11\x65\x28\x64\xc4\x59\x6c\xbd\x6c\xf0\x02\xe5\x84\x69\x44\x09\x94\xcb\x02\x09\x92\xab\x06\x0b\x4b\xa5\x78\xfe\x2d\xaa\x3f\xc5\x1b\xfd\x2e\xe6\x7d\xf8\x2b\x7f\x24\xc2\xe0\xeb\xa5\x7e\x87\xf8\x0f\x41\xa4\x24\x36\xb8\xc5\x5c\xbf\xab\x96\xab\xc4\x82\x33\xf6\x19\x89\x51\xff\x16\xbd\x4b\xcb\x2c\x74\xbe\xfe\xb8\xf1\x00\xae\x8c\xa4\x52\x1d\x18\x71\x24\x05\x9a\x86\x8c\x1d\x6a\x8d\x0f\x85\x89\xdd\x68\xed\x37\xd6\x98\x5b\x19\x13\x2e\x48\x52\x32\x58\xbe\x85\x24\x7d\xd2\x1f\x04\x41\x6b\x31\x69\xd3\xb3\x3f\xf5\x9d\xa0\x1c\xa7\x16\x60\x24\x76\xec\x3b\xed\x3a\x30\xa3\xaa\x26\x82\xb0\xa2\x3c\xff\xfe\x3f\x82\x3b\x7a\x52\x60\xf8\xbf\x9f\xf9\xd3\xd9\xcd\x6a\xeb\x4f\xbd\x48\xaa\xbe\x81\xc7\xb1\x24\xc6\xb6\x02\xdb\xb6\x1a\x50\xb9\x59\xf7\xc8\xd8\xac\x8e\xf1\xa8\x91\x11\x85\x30\x14\x15\x71\x88\x84\x08\x3f\x24\x42\xd7\xac\x74\x1c\x01\x40\x6d\xad\x1d\x5b\x9f\x34\x43\x09\xf1\x7c\x97\xa9\x53\x95\x60\xdf\xd1\x48\xa5\x60\x5f\xac\x80\x90\x97\x92\xba\x73\x82\x35\x8c\x32\x1e\x19\x27\x52\x09\xa2\x70\xea\xb5\xcc\x74\x2b\xd5\x65\x7b\xc2\x52\xb6\xc2\xdf\xf0\x57\xb4\x65\x7e\x82\xc7\xa6\x27\xc6\x7c\xd6\x2e\xab\x9e\xe8\x32\x43\xd0\x27\x97\xa9\x7c\x60\xb4\xbe\x56\xe5\xd1\x9e\xca\x4b\x15\x7e\xd6\x2f\xf9\xe5\x55\x09\xdf\x1c\x4c\xef\xd0\x3c\xa2\x52\x9f\x7b\x3a\x42\x25\x4a\x72\x9e\x5c\xd8\xb8\xfe\x76\xe7\x19\xa5\xa8\x50\x78\xa1\xa5\xb5\xb9\xe6\x96\x96\x8c\x46\x36\xdc\xa1\xe6\x35\xd2\x2a\x9b\x51\x9d\x9a\x59\x37\x46\x7b\x2d\x47\x77\x40\x36\x9d\xb8\xea\xe6\xb4\xaa\x2d\x5b\x93\xd2\x57\x98\x5f\xf6\xf0\xbe\x07\x2d\x83\xf9\x76\x70\xc6\x76\x53\x05\x24\xf3\x2d\xde\xdd\xb5\xe9\x35\x1d\xed\xb2\xa7\xf9\xb2\x97\xac\x57\xbd\xc5\x1c\x97\x20\xf3\xdd\x29\x4e\xbb\x3b\xec\x8b\x49\x69\x92\x81\x53\x82\x1f\x42\xbe\x1f\x4b\x47\x8b\x3d\x17\x7c\x44\x6f\xc7\x36\x10\xd5\x36\xd8\x59\x54\x80\xbb\xd2\x99\xb2\xe3\xde\xf3\xdf\xd5\x37\x7d\x39\x0f\xd8\xf6\xfc\x33\x2d\x0d\x70\xde\x5b\x66\x82\x0a\xb0\xae\x5f\x92\xfd\xdf\x30\x84\xab\xaa\xa2\x79\xf2\x93\xef\xc6\x68\xc2\x16\x49\x9f\x80\x1b\x62\x5d\x98\x05\x8a\x22\xf8\xc2\x46\xc1\x0b\x13\x23\xec\x9a\x1c\x16\xcd\xf4\x60\xe0\xdf\xa4\x6f\x74\x77\xb0\x88\xb3\xef\x8a\xdf\x75\x3d\x2e\x5d\xe3\x25\xb5\xef\xa4\xd9\xfe\xaa\x49\x35\xf9\x03\x27\xc4\x92\x21\x9c\x0e\x00\x00")
+
+func assetsThemeDefaultJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsThemeDefaultJson,
+		"assets/theme/default.json",
+	)
+}
+
+func assetsThemeDefaultJson() (*asset, error) {
+	bytes, err := assetsThemeDefaultJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/theme/default.json", size: 3740, mode: os.FileMode(420), modTime: time.Unix(1792429209, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _assetsThemeHighcontrastJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdd\x56\x4d\x6f\xdb\x30\x0c\xbd\xe7\x57\x18\xee\x35\x28\xec\x36\xc9\x8a\x9e\x86\xed\xd2\x01\x3b\x0c\x5b\x81\x1d\x8a\x1c\x64\x49\xb6\x85\xca\x96\x21\xc9\x4d\xd2\xc0\xff\x7d\x94\xe4\xef\xba\x6d\x86\x66\x43\x90\x04\x41\xa0\xf7\x64\x9a\x7c\x24\x25\xee\x67\x9e\xe7\xe7\x28\xa3\xfe\xad\xe7\xdf\xb1\x24\xf5\xbe\x8a\x5c\x4b\xa4\xb4\x3f\x37\x14\x16\x5c\x48\x05\xe4\x1e\x56\xb0\xd6\x74\xab\xcd\xd6\x8b\xd8\x7e\xec\x26\x80\x53\x78\x92\xc3\xaf\xe3\x82\xa0\xe3\xf2\x29\xb8\xcc\x23\x51\xe6\xa4\x66\x56\xc1\xaa\xc7\xa0\x27\xc4\x38\x8a\xb8\xf5\xea\x02\x05\xe6\xdb\xb0\x05\xca\x29\xb7\x78\x60\x3f\x0d\x1e\x95\x5a\x8b\xfc\x1b\xa9\x1f\x9a\x22\xef\xc4\x13\x95\x35\xbb\x0c\xf0\xcd\x90\xfd\x21\xa9\x52\x94\xd4\xfc\xf5\xd5\xa7\x96\x57\x58\x0a\xce\xbf\x20\x39\x61\xd9\x71\xf7\x69\x99\x45\x7d\x5d\x80\xac\xac\x80\x4a\xef\x38\xed\x09\x08\x61\x59\xf7\xdd\xd2\x28\xba\x2b\xac\xc7\x56\xd9\x79\x83\xc6\xc2\x89\x96\x88\x48\x70\xd2\xe1\x8a\x3d\x9b\xdd\x61\xd8\x22\x36\x43\x66\xeb\xe7\xa1\x05\x94\xe3\xd4\x11\x9c\xc6\x3d\x7c\x63\xec\x86\x76\x55\xd5\x31\x20\xac\x99\xc8\xbf\xff\x77\xcf\xf6\xbe\x92\x18\xfe\x1f\x82\xb9\x17\x5c\x2e\xd7\x73\xcf\x27\x4a\x0f\x01\x11\xc7\x8a\x5a\x6c\x09\xd8\xba\x1a\xc5\x71\xb9\x1a\x44\xe2\x32\x39\x15\x44\xcd\x4c\xc8\x83\x69\xae\xa1\x2c\x5a\x26\x42\xf8\x31\x91\xa6\x36\x55\xcf\x10\x10\xcc\xd5\xd6\xbe\xb5\xc9\x32\x94\x50\x7f\xde\x8f\xb4\x57\x85\x80\x6f\x18\xd1\x29\xe0\xd7\x4b\x08\xc8\x4f\x69\xdd\x21\xe1\x0a\x56\x99\x20\xd6\x88\xd2\x92\x6a\x9c\xfa\x6d\x64\xa6\x65\xea\x42\x3d\xe0\x55\xae\xa6\xdf\xb1\x57\xb4\xa5\x7d\x80\xc5\xa6\x0f\xa6\x6c\xd6\x26\xab\x81\xe8\x2a\x43\xd0\x1d\xa7\xa9\x7c\x68\xb5\x3e\x57\xe5\xd1\x96\xa9\x53\x15\x3e\x18\x96\xfc\xe2\xac\x84\x6f\x2e\xa1\x0f\x68\x4e\x98\x32\x37\x9c\xf1\x50\xcb\x92\x1e\x27\x17\xce\xaf\xbf\x3d\x79\x26\x43\xd4\x28\x3a\xd1\xd2\xba\x39\xe7\x96\x56\x9c\x11\xe7\xee\x58\xf3\x9a\x69\x95\xcd\x98\x49\x4d\xd0\xad\xd1\xd6\xc8\xd1\x5d\x90\x4d\x27\x2e\xbb\x3d\xad\x6a\x8b\x16\xd2\x66\x74\xf9\xe5\x2e\xef\x07\xd0\x32\xbc\x5a\x8f\xee\xd8\x6e\xab\x84\x64\xbe\x17\x77\x37\x2c\xbd\xa5\xa3\x7b\xed\x61\xb6\xdc\x70\xf5\xa6\xb5\x58\xe0\x12\x64\xbe\x3f\xc4\x68\x37\xab\xbe\x9a\x94\x26\x19\x38\xa5\xf8\x31\x12\xdb\xa9\x74\xb4\xdc\x4b\xc1\x27\xf4\xee\x61\x23\x51\x5d\x83\x1d\x45\x05\x98\x95\x8e\x94\x9d\xfe\x3c\xff\xa1\xbe\x19\xca\xb9\xc3\xae\xe7\x5f\x68\x69\x89\x23\x8e\x98\x09\x2a\x00\x5d\xbd\xa6\xf9\xbf\x09\x0f\xe6\x54\xcd\xf2\xe4\xa7\xd8\x4c\xc5\x08\xe7\x23\x7b\x86\xc0\x10\xef\xdc\x2c\x10\x21\xf0\x84\xf3\x42\x14\xd6\x47\x38\x32\x05\xbc\x34\x33\x8b\x91\x7d\x9b\xbb\xc9\xa3\xc1\x31\xbd\x43\x57\xfe\xae\x8b\x71\xd1\x07\x4f\xa9\x77\x67\xcd\xd9\x57\xcd\xaa\xd9\x1f\xa1\x09\xf5\x7f\x87\x0e\x00\x00")
+
+func assetsThemeHighcontrastJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsThemeHighcontrastJson,
+		"assets/theme/highcontrast.json",
+	)
+}
+
+func assetsThemeHighcontrastJson() (*asset, error) {
+	bytes, err := assetsThemeHighcontrastJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/theme/highcontrast.json", size: 3719, mode: os.FileMode(420), modTime: time.Unix(1792429209, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -140,7 +350,17 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"assets/psd/test.psd": assetsPsdTestPsd,
+	"assets/psd/test.psd":              assetsPsdTestPsd,
+	"assets/theme/default.json":        assetsThemeDefaultJson,
+	"assets/theme/highcontrast.json":   assetsThemeHighcontrastJson,
+	"assets/ui/axismenu.json":          assetsUiAxismenuJson,
+	"assets/ui/mainmenu.json":          assetsUiMainmenuJson,
+	"assets/ui/pausemenu.json":         assetsUiPausemenuJson,
+	"assets/ui/settings/audio.json":    assetsUiSettingsAudioJson,
+	"assets/ui/settings/controls.json": assetsUiSettingsControlsJson,
+	"assets/ui/settings/gameplay.json": assetsUiSettingsGameplayJson,
+	"assets/ui/settings/video.json":    assetsUiSettingsVideoJson,
+	"assets/ui/settingsmenu.json":      assetsUiSettingsmenuJson,
 }
 
 // AssetDir returns the file names below a certain
@@ -187,6 +407,22 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"psd": &bintree{nil, map[string]*bintree{
 			"test.psd": &bintree{assetsPsdTestPsd, map[string]*bintree{}},
 		}},
+		"theme": &bintree{nil, map[string]*bintree{
+			"default.json":      &bintree{assetsThemeDefaultJson, map[string]*bintree{}},
+			"highcontrast.json": &bintree{assetsThemeHighcontrastJson, map[string]*bintree{}},
+		}},
+		"ui": &bintree{nil, map[string]*bintree{
+			"axismenu.json":  &bintree{assetsUiAxismenuJson, map[string]*bintree{}},
+			"mainmenu.json":  &bintree{assetsUiMainmenuJson, map[string]*bintree{}},
+			"pausemenu.json": &bintree{assetsUiPausemenuJson, map[string]*bintree{}},
+			"settings": &bintree{nil, map[string]*bintree{
+				"audio.json":    &bintree{assetsUiSettingsAudioJson, map[string]*bintree{}},
+				"controls.json": &bintree{assetsUiSettingsControlsJson, map[string]*bintree{}},
+				"gameplay.json": &bintree{assetsUiSettingsGameplayJson, map[string]*bintree{}},
+				"video.json":    &bintree{assetsUiSettingsVideoJson, map[string]*bintree{}},
+			}},
+			"settingsmenu.json": &bintree{assetsUiSettingsmenuJson, map[string]*bintree{}},
+		}},
 	}},
 }}
 
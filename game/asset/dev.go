@@ -0,0 +1,154 @@
+package asset
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// pollInterval is the minimum time between checks for modified files in development mode.
+const pollInterval = 500 * time.Millisecond
+
+var (
+	// devMode is true when assets are read from a directory and watched for changes.
+	devMode  bool
+	watchers []*watcher
+	lastPoll time.Time
+)
+
+// watcher calls fn when any of the files at paths are modified. modTime returns the
+// modification time of one of the paths.
+type watcher struct {
+	paths    []string
+	modTimes []time.Time
+	modTime  func(path string) time.Time
+	fn       func() error
+}
+
+// UseDir switches to development mode. Assets are read from dir, which should have the
+// same layout as the embedded assets directory, e.g. "game/asset/assets". Files are then
+// watched for changes, see Poll.
+func UseDir(dir string) {
+	SetFS(Dir(dir))
+	devMode = true
+	log.Println("Reading assets from", dir)
+}
+
+// WatchPsd calls fn each time the given PSD file changes. The cached copy has already been
+// dropped so fn can call Psd to get the new Descs. If fn returns an error it's called again
+// on the next Poll, see Poll. It does nothing unless in development mode.
+func WatchPsd(name string, fn func() error) {
+	watch([]string{psdPath(name)}, fn)
+}
+
+// WatchSheet calls fn each time the given sprite sheet's png image or JSON description
+// changes. The cached copy has already been dropped so fn can call Sheet to get the new
+// Descs. It does nothing unless in development mode.
+func WatchSheet(name string, fn func() error) {
+	png, desc := sheetPaths(name)
+	watch([]string{png, desc}, fn)
+}
+
+// WatchUI calls fn each time the given ui definition changes. The cached copy has already
+// been dropped so fn can call UI to get the new Def. It does nothing unless in development
+// mode.
+func WatchUI(name string, fn func() error) {
+	watch([]string{uiPath(name)}, fn)
+}
+
+// WatchTheme calls fn each time the given ui theme changes. The cached copy has already
+// been dropped so fn can call Theme to get the new Theme. It does nothing unless in
+// development mode.
+func WatchTheme(name string, fn func() error) {
+	watch([]string{themePath(name)}, fn)
+}
+
+// WatchLevel calls fn each time the given level changes. Levels aren't cached so fn can
+// call Level to decode the new one. It does nothing unless in development mode.
+func WatchLevel(name string, fn func() error) {
+	watch([]string{levelPath(name)}, fn)
+}
+
+// WatchFile calls fn each time the file at path changes. Unlike the other watchers, path
+// is on the OS's filesystem rather than an asset, e.g. a config file that the game reads
+// itself. It does nothing unless in development mode.
+func WatchFile(path string, fn func() error) {
+	watchWith(osModTime, []string{path}, fn)
+}
+
+func watch(paths []string, fn func() error) {
+	watchWith(modTime, paths, fn)
+}
+
+func watchWith(modTime func(path string) time.Time, paths []string, fn func() error) {
+	if !devMode {
+		return
+	}
+	w := &watcher{
+		paths:    paths,
+		modTimes: make([]time.Time, len(paths)),
+		modTime:  modTime,
+		fn:       fn,
+	}
+	for i, p := range paths {
+		w.modTimes[i] = modTime(p)
+	}
+	watchers = append(watchers, w)
+}
+
+func modTime(path string) time.Time {
+	info, err := fsys.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func osModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Poll checks watched files for changes and calls the functions watching them. It should
+// be called regularly, e.g. every frame, from the same goroutine that uses the assets. It
+// does nothing unless in development mode.
+//
+// The old copy of a changed asset is forgotten rather than unloaded since it may still be
+// in use, and a file that is in the middle of being written may fail to load. Watchers
+// should keep using what they have and return an error when loading fails, in which case
+// they're called again on each Poll until they succeed.
+func Poll() {
+	if !devMode || time.Since(lastPoll) < pollInterval {
+		return
+	}
+	lastPoll = time.Now()
+
+	for _, w := range watchers {
+		changed := false
+		for i, p := range w.paths {
+			t := w.modTime(p)
+			if t.After(w.modTimes[i]) {
+				w.modTimes[i] = t
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		log.Println("Reloading assets", w.paths)
+		for _, p := range w.paths {
+			forget(p)
+		}
+		if err := w.fn(); err != nil {
+			log.Printf("Reloading: %v", err)
+			// Try again next time, the files may have been in the middle of being written.
+			for i := range w.modTimes {
+				w.modTimes[i] = time.Time{}
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package asset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// builtinFonts can be loaded by name even when the FS has no file for them.
+var builtinFonts = map[string][]byte{
+	"goregular": goregular.TTF,
+	"gobold":    gobold.TTF,
+	"gomono":    gomono.TTF,
+}
+
+// Font loads the TrueType font "font/<name>.ttf". If there is no such file then name may
+// also be one of the built in Go fonts: "goregular", "gobold" or "gomono".
+func Font(name string) (*truetype.Font, error) {
+	p := fontPath(name)
+	if f, ok := cached(p).(*truetype.Font); ok {
+		return f, nil
+	}
+	data, err := fsys.ReadFile(p)
+	if err != nil {
+		builtin, ok := builtinFonts[name]
+		if !ok {
+			return nil, fmt.Errorf("load font '%s': %v", name, err)
+		}
+		data = builtin
+	}
+	f, err := truetype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("load font '%s': %v", name, err)
+	}
+	store(p, f)
+	return f, nil
+}
+
+// Face returns a face for the named font, see Font, where size is the height of an em in
+// pixels. Faces are cached for each size and are unloaded along with their font.
+func Face(name string, size float64) (font.Face, error) {
+	p := facePath(name, size)
+	if face, ok := cached(p).(font.Face); ok {
+		return face, nil
+	}
+	f, err := Font(name)
+	if err != nil {
+		return nil, err
+	}
+	face := truetype.NewFace(f, &truetype.Options{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	store(p, face)
+	return face, nil
+}
+
+// facePath is the key that a face is cached under. It begins with its font's path so
+// that unloading the font can find it.
+func facePath(name string, size float64) string {
+	return fmt.Sprintf("%s@%g", fontPath(name), size)
+}
+
+// unloadFaces unloads the faces cached for the font at fontPath.
+func unloadFaces(fontPath string) {
+	cacheMu.Lock()
+	var paths []string
+	for p := range cache {
+		if strings.HasPrefix(p, fontPath+"@") {
+			paths = append(paths, p)
+		}
+	}
+	cacheMu.Unlock()
+	for _, p := range paths {
+		Unload(p)
+	}
+}
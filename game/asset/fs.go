@@ -0,0 +1,101 @@
+package asset
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// FS provides access to a tree of asset files. Names are slash separated and relative to
+// the root of the tree, e.g. "psd/test.psd".
+type FS interface {
+	// ReadFile returns the contents of the named file.
+	ReadFile(name string) ([]byte, error)
+	// Stat returns information about the named file.
+	Stat(name string) (os.FileInfo, error)
+}
+
+// embeddedFS reads the assets that were embedded into the binary with go-bindata.
+type embeddedFS struct{}
+
+// Embedded returns an FS for the assets compiled into the binary.
+func Embedded() FS {
+	return embeddedFS{}
+}
+
+func (embeddedFS) ReadFile(name string) ([]byte, error) {
+	return Asset(path.Join(root, name))
+}
+
+func (embeddedFS) Stat(name string) (os.FileInfo, error) {
+	return AssetInfo(path.Join(root, name))
+}
+
+// dirFS reads assets from a directory.
+type dirFS string
+
+// Dir returns an FS for the assets in the given directory, e.g. "game/asset/assets".
+func Dir(dir string) FS {
+	return dirFS(dir)
+}
+
+func (d dirFS) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+func (d dirFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+// zipFS reads assets from a zip archive.
+type zipFS struct {
+	files map[string]*zip.File
+}
+
+// Zip returns an FS for the assets in a zip archive. The root of the archive is the root
+// of the FS.
+func Zip(data []byte) (FS, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open zip archive: %v", err)
+	}
+	z := zipFS{files: map[string]*zip.File{}}
+	for _, f := range r.File {
+		z.files[f.Name] = f
+	}
+	return z, nil
+}
+
+// ZipFile is like Zip but reads the archive from the named file.
+func ZipFile(filename string) (FS, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Zip(data)
+}
+
+func (z zipFS) ReadFile(name string) ([]byte, error) {
+	f, ok := z.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func (z zipFS) Stat(name string) (os.FileInfo, error) {
+	f, ok := z.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return f.FileInfo(), nil
+}
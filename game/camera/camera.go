@@ -21,6 +21,7 @@ type Target interface {
 type Camera struct {
 	pos      geo.Vec
 	offset   geo.Vec
+	size     geo.Vec
 	halfSize geo.Vec
 	Target   Target
 	MaxDist  float64
@@ -28,45 +29,72 @@ type Camera struct {
 	Ease     geo.EaseFn
 	// Shaker is optional. Set its fields and call the Camera's StartShake functions. If
 	// Shaker.Falloff is nil then the Shaker's ShakeConst is used.
-	Shaker     geo.Shaker
-	shakerTime time.Time
+	Shaker geo.Shaker
+	// now is the Camera's own clock. It only advances in Update so that the Camera's
+	// output depends only on the sequence of dt values it is given.
+	now     time.Time
+	shaking bool
 }
 
 // New creates, initializes, and returns a new Camera. The parameters width and height
 // are the dimensions of the image tha Camera will be used to draw to. The default Ease
 // fuction is linear, MaxSpeed=0, and MaxDist=0. This results in perfectly sticking
-// to the Target, though MaxDist=0 on its own is sufficient for that behavior.
+// to the Target, though MaxDist=0 on its own is sufficient for that behavior. The
+// Camera's clock starts at the zero time.
 func New(width, height int) *Camera {
+	return NewAt(width, height, time.Time{})
+}
+
+// NewAt is like New but the Camera's clock starts at the given time instead of the zero
+// time.
+func NewAt(width, height int, start time.Time) *Camera {
 	return &Camera{
-		halfSize:   geo.VecXYi(width/2, height/2),
-		Ease:       geo.EaseLinear,
-		shakerTime: time.Now(),
+		size:     geo.VecXYi(width, height),
+		halfSize: geo.VecXYi(width/2, height/2),
+		Ease:     geo.EaseLinear,
+		now:      start,
 	}
 }
 
 // Update updates the Camera's state simulating dt time passed.
 func (c *Camera) Update(dt time.Duration) {
-	c.shakerTime = c.shakerTime.Add(dt)
+	c.now = c.now.Add(dt)
 
 	target := c.Target.Pos()
 	distToTarget2 := target.Dist2(c.pos)
 	max2 := c.MaxDist * c.MaxDist
 	if distToTarget2 > max2 {
 		c.pos = target.Plus(c.pos.Minus(target).WithLen(c.MaxDist))
-	} else {
+	} else if distToTarget2 > 0 {
 		ratio := distToTarget2 / max2
 		speed := c.Ease(ratio) * c.MaxSpeed
 		vel := target.Minus(c.pos).WithLen(speed)
 		c.pos.Add(vel.Times(dt.Seconds()))
 	}
 
-	if c.Shaker.Falloff != nil {
-		c.offset = c.Shaker.Shake(c.shakerTime)
-	} else {
-		c.offset = c.Shaker.ShakeConst(c.shakerTime)
+	c.offset = geo.Vec0
+	if c.shaking && c.now.Sub(c.Shaker.StartTime) > c.Shaker.Duration {
+		c.shaking = false
+	}
+	if c.shaking {
+		if c.Shaker.Falloff != nil {
+			c.offset = c.Shaker.Shake(c.now)
+		} else {
+			c.offset = c.Shaker.ShakeConst(c.now)
+		}
 	}
 }
 
+// Size returns the dimensions of the image the Camera draws to.
+func (c *Camera) Size() geo.Vec {
+	return c.size
+}
+
+// Time returns the current time of the Camera's clock.
+func (c *Camera) Time() time.Time {
+	return c.now
+}
+
 // ScreenCoords takes a position in world coordinates and returns its position on the screen.
 func (c *Camera) ScreenCoords(pos geo.Vec) geo.Vec {
 	return pos.Minus(c.topLeft())
@@ -81,14 +109,27 @@ func (c *Camera) topLeft() geo.Vec {
 	return c.Center().Minus(c.halfSize)
 }
 
-// Center returns the camera's center position in world coordinates.
+// ScreenCoordsExact is like ScreenCoords but uses CenterExact, so the result is not
+// snapped to the pixel grid.
+func (c *Camera) ScreenCoordsExact(pos geo.Vec) geo.Vec {
+	return pos.Minus(c.CenterExact().Minus(c.halfSize))
+}
+
+// Center returns the camera's center position in world coordinates, snapped to the
+// pixel grid.
 func (c *Camera) Center() geo.Vec {
-	cameraCenter := c.pos.Plus(c.offset)
+	cameraCenter := c.CenterExact()
 	cameraCenter.Floor()
 	return cameraCenter
 }
 
-// StartShake restarts the Shaker time.
+// CenterExact returns the camera's sub-pixel center position in world coordinates.
+func (c *Camera) CenterExact() geo.Vec {
+	return c.pos.Plus(c.offset)
+}
+
+// StartShake restarts the Shaker time. The shake ends after Shaker.Duration.
 func (c *Camera) StartShake() {
-	c.Shaker.StartTime = c.shakerTime
+	c.Shaker.StartTime = c.now
+	c.shaking = true
 }
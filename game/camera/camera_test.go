@@ -0,0 +1,163 @@
+package camera
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Bredgren/geo"
+)
+
+type target struct {
+	pos geo.Vec
+}
+
+func (t *target) Pos() geo.Vec {
+	return t.pos
+}
+
+const epsilon = 1e-9
+
+func near(a, b geo.Vec) bool {
+	return math.Abs(a.X-b.X) < epsilon && math.Abs(a.Y-b.Y) < epsilon
+}
+
+func TestFollow(t *testing.T) {
+	tgt := &target{geo.VecXY(50, 0)}
+	c := NewAt(100, 100, time.Time{})
+	c.Target = tgt
+	c.MaxDist = 100
+	c.MaxSpeed = 200
+
+	// The speed is MaxSpeed scaled by Ease of (50/100)^2, so 50 px/s for 100ms.
+	c.Update(100 * time.Millisecond)
+	if got, want := c.CenterExact(), geo.VecXY(5, 0); !near(got, want) {
+		t.Fatalf("center = %v, want %v", got, want)
+	}
+
+	// It keeps closing in without passing the target.
+	last := c.CenterExact().X
+	for i := 0; i < 100; i++ {
+		c.Update(100 * time.Millisecond)
+		x := c.CenterExact().X
+		if x < last || x > tgt.pos.X {
+			t.Fatalf("step %d: x = %v after %v, target %v", i, x, last, tgt.pos.X)
+		}
+		last = x
+	}
+	if last == 5 {
+		t.Errorf("camera stopped following")
+	}
+}
+
+func TestMaxDist(t *testing.T) {
+	tgt := &target{geo.VecXY(300, -400)}
+	c := NewAt(100, 100, time.Time{})
+	c.Target = tgt
+	c.MaxDist = 100
+	c.MaxSpeed = 50
+
+	c.Update(time.Millisecond)
+	// The camera is pulled straight toward the target until it's MaxDist away.
+	if got, want := c.CenterExact(), geo.VecXY(240, -320); !near(got, want) {
+		t.Errorf("center = %v, want %v", got, want)
+	}
+
+	// With no MaxDist the camera sticks to the target.
+	c.MaxDist = 0
+	tgt.pos = geo.VecXY(-20.5, 7.25)
+	c.Update(time.Millisecond)
+	if got := c.CenterExact(); !near(got, tgt.pos) {
+		t.Errorf("center = %v, want %v", got, tgt.pos)
+	}
+}
+
+func TestShake(t *testing.T) {
+	tgt := &target{geo.VecXY(10, 20)}
+	c := NewAt(100, 100, time.Time{})
+	c.Target = tgt
+	c.Shaker.Amplitude = 30
+	c.Shaker.Duration = time.Second
+	c.Shaker.Frequency = 10
+	c.Shaker.Falloff = geo.EaseLinear
+
+	c.Update(time.Millisecond)
+	if got := c.CenterExact(); !near(got, tgt.pos) {
+		t.Fatalf("center before shaking = %v, want %v", got, tgt.pos)
+	}
+
+	c.StartShake()
+	var early, late float64
+	for elapsed := time.Duration(0); elapsed < c.Shaker.Duration; elapsed += time.Millisecond {
+		c.Update(time.Millisecond)
+		offset := c.CenterExact().Minus(tgt.pos)
+		if math.Abs(offset.X) > c.Shaker.Amplitude || math.Abs(offset.Y) > c.Shaker.Amplitude {
+			t.Fatalf("offset %v is larger than the amplitude", offset)
+		}
+		switch {
+		case elapsed < 200*time.Millisecond:
+			early = math.Max(early, offset.Len())
+		case elapsed > 800*time.Millisecond:
+			late = math.Max(late, offset.Len())
+		}
+	}
+	if early == 0 {
+		t.Fatalf("camera didn't shake")
+	}
+	if late >= early {
+		t.Errorf("shake didn't decay, largest offset %v at the start and %v at the end", early, late)
+	}
+
+	c.Update(10 * time.Millisecond)
+	if got := c.CenterExact(); !near(got, tgt.pos) {
+		t.Errorf("center after shaking = %v, want %v", got, tgt.pos)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	newCam := func() *Camera {
+		c := NewAt(100, 100, start)
+		c.Target = &target{geo.VecXY(200, 50)}
+		c.MaxDist = 150
+		c.MaxSpeed = 300
+		c.Shaker.Amplitude = 5
+		c.Shaker.Duration = time.Second
+		c.Shaker.Frequency = 10
+		c.Shaker.Falloff = geo.EaseLinear
+		return c
+	}
+	a, b := newCam(), newCam()
+	a.StartShake()
+	b.StartShake()
+	dts := []time.Duration{16 * time.Millisecond, 17 * time.Millisecond, 33 * time.Millisecond, 5 * time.Millisecond}
+	for i := 0; i < 40; i++ {
+		dt := dts[i%len(dts)]
+		a.Update(dt)
+		b.Update(dt)
+		if a.CenterExact() != b.CenterExact() {
+			t.Fatalf("step %d: %v != %v", i, a.CenterExact(), b.CenterExact())
+		}
+	}
+}
+
+func TestViewportCoords(t *testing.T) {
+	c := NewAt(100, 100, time.Time{})
+	v := NewViewport(c, geo.RectXYWH(10, 20, 50, 200))
+
+	pos := geo.VecXY(12, -34)
+	if got := v.WorldCoords(v.ScreenCoords(pos)); !near(got, pos) {
+		t.Errorf("round trip of %v = %v", pos, got)
+	}
+
+	// A collapsed Viewport maps to the Camera's center on the collapsed axes.
+	v.Bounds = geo.RectXYWH(10, 20, 0, 200)
+	got := v.WorldCoords(geo.VecXY(10, 20))
+	if math.IsNaN(got.X) || math.IsInf(got.X, 0) || got.X != c.Center().X {
+		t.Errorf("x = %v, want %v", got.X, c.Center().X)
+	}
+	v.Bounds = geo.RectXYWH(10, 20, 0, 0)
+	if got := v.WorldCoords(geo.VecXY(10, 20)); !near(got, c.Center()) {
+		t.Errorf("collapsed = %v, want %v", got, c.Center())
+	}
+}
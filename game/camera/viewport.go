@@ -0,0 +1,93 @@
+package camera
+
+import (
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// DrawFn draws the world, as seen by cam, to dst.
+type DrawFn func(dst *ebiten.Image, cam *Camera)
+
+// Viewport pairs a Camera with the rectangle of the destination image that it is drawn
+// to. The Camera's view is scaled to fill Bounds, so a Camera that is larger than Bounds
+// can be used for a zoomed out view such as a minimap.
+type Viewport struct {
+	Camera *Camera
+	// Bounds is the area of the destination image, in pixels, that the Camera's view is
+	// drawn to.
+	Bounds geo.Rect
+	img    *ebiten.Image
+}
+
+// NewViewport creates, initializes, and returns a new Viewport that draws cam's view
+// within bounds.
+func NewViewport(cam *Camera, bounds geo.Rect) *Viewport {
+	return &Viewport{
+		Camera: cam,
+		Bounds: bounds,
+	}
+}
+
+// Draw calls draw with an image the size of the Camera and then draws that image to
+// dst within Bounds. If Bounds is the whole of dst and the same size as the Camera then
+// draw is given dst directly. Nothing is drawn if Bounds or the Camera has no area.
+func (v *Viewport) Draw(dst *ebiten.Image, draw DrawFn) {
+	camSize := v.Camera.Size()
+	if v.Bounds.W <= 0 || v.Bounds.H <= 0 || camSize.X <= 0 || camSize.Y <= 0 {
+		return
+	}
+	dstBounds := geo.RectWH(geo.I2F2(dst.Size()))
+	if v.Bounds == dstBounds && camSize.X == v.Bounds.W && camSize.Y == v.Bounds.H {
+		draw(dst, v.Camera)
+		return
+	}
+
+	if v.img == nil || geo.VecXYi(v.img.Size()) != camSize {
+		if v.img != nil {
+			v.img.Dispose()
+		}
+		v.img, _ = ebiten.NewImage(int(camSize.X), int(camSize.Y), ebiten.FilterLinear)
+	}
+	v.img.Clear()
+	draw(v.img, v.Camera)
+
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(v.Bounds.W/camSize.X, v.Bounds.H/camSize.Y)
+	opts.GeoM.Translate(v.Bounds.TopLeft())
+	dst.DrawImage(v.img, &opts)
+}
+
+// ScreenCoords takes a position in world coordinates and returns its position on the
+// destination image given to Draw.
+func (v *Viewport) ScreenCoords(pos geo.Vec) geo.Vec {
+	camPos := v.Camera.ScreenCoords(pos)
+	camSize := v.Camera.Size()
+	return geo.VecXY(v.Bounds.X+camPos.X*v.Bounds.W/camSize.X, v.Bounds.Y+camPos.Y*v.Bounds.H/camSize.Y)
+}
+
+// WorldCoords takes a position on the destination image given to Draw and returns its
+// position in the world. If Bounds has no width or height, e.g. while the Viewport is
+// collapsed, then that coordinate is the Camera's center.
+func (v *Viewport) WorldCoords(pos geo.Vec) geo.Vec {
+	camSize := v.Camera.Size()
+	camPos := camSize.Times(0.5)
+	if v.Bounds.W != 0 {
+		camPos.X = (pos.X - v.Bounds.X) * camSize.X / v.Bounds.W
+	}
+	if v.Bounds.H != 0 {
+		camPos.Y = (pos.Y - v.Bounds.Y) * camSize.Y / v.Bounds.H
+	}
+	return v.Camera.WorldCoords(camPos)
+}
+
+// SplitScreen divides bounds into n equal side by side columns and returns a Viewport
+// for each, along with its own Camera sized to match.
+func SplitScreen(bounds geo.Rect, n int) []*Viewport {
+	viewports := make([]*Viewport, n)
+	w := bounds.W / float64(n)
+	for i := range viewports {
+		b := geo.RectXYWH(bounds.X+float64(i)*w, bounds.Y, w, bounds.H)
+		viewports[i] = NewViewport(New(int(b.W), int(b.H)), b)
+	}
+	return viewports
+}
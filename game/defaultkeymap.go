@@ -3,6 +3,7 @@ package game
 import (
 	"github.com/Bredgren/game1/game/keymap"
 	"github.com/Bredgren/game1/game/keymap/button"
+	"github.com/Bredgren/game1/game/ui"
 	"github.com/hajimehoshi/ebiten"
 )
 
@@ -30,15 +31,7 @@ import (
 // Axis5 Right Trigger (-1 default)
 
 func setDefaultKeyMap(km *keymap.KeyMap) {
-	for _, btn := range km.KeyMouse.Buttons() {
-		km.KeyMouse.DelButton(btn)
-	}
-	for _, btn := range km.GamepadBtn.Buttons() {
-		km.GamepadBtn.DelButton(btn)
-	}
-	for _, axis := range km.GamepadAxis.Axes() {
-		km.GamepadAxis.DelAxis(axis)
-	}
+	clearKeyMap(km)
 
 	km.KeyMouse.Set(button.FromKey(ebiten.KeyA), left)
 	km.KeyMouse.Set(button.FromKey(ebiten.KeyD), right)
@@ -61,6 +54,38 @@ func setDefaultKeyMap(km *keymap.KeyMap) {
 	km.GamepadAxis.Set(3, punchV)
 }
 
+// clearKeyMap removes all of km's bindings.
+func clearKeyMap(km *keymap.KeyMap) {
+	for _, btn := range km.KeyMouse.Buttons() {
+		km.KeyMouse.DelButton(btn)
+	}
+	for _, btn := range km.GamepadBtn.Buttons() {
+		km.GamepadBtn.DelButton(btn)
+	}
+	for _, axis := range km.GamepadAxis.Axes() {
+		km.GamepadAxis.DelAxis(axis)
+	}
+}
+
+// setDefaultUIKeyMap binds buttons to the actions handled by a ui.Root's KeyMap.
+func setDefaultUIKeyMap(km *keymap.KeyMap) {
+	km.KeyMouse.Set(button.FromMouse(ebiten.MouseButtonLeft), ui.ActionClick)
+	km.KeyMouse.Set(button.FromKey(ebiten.KeyUp), ui.ActionUp)
+	km.KeyMouse.Set(button.FromKey(ebiten.KeyDown), ui.ActionDown)
+	km.KeyMouse.Set(button.FromKey(ebiten.KeyLeft), ui.ActionLeft)
+	km.KeyMouse.Set(button.FromKey(ebiten.KeyRight), ui.ActionRight)
+	km.KeyMouse.Set(button.FromKey(ebiten.KeyEnter), ui.ActionConfirm)
+
+	km.GamepadBtn.Set(ebiten.GamepadButton10, ui.ActionUp)
+	km.GamepadBtn.Set(ebiten.GamepadButton12, ui.ActionDown)
+	km.GamepadBtn.Set(ebiten.GamepadButton13, ui.ActionLeft)
+	km.GamepadBtn.Set(ebiten.GamepadButton11, ui.ActionRight)
+	km.GamepadBtn.Set(ebiten.GamepadButton0, ui.ActionConfirm)
+
+	km.GamepadAxis.Set(1, ui.ActionNavY)
+	km.GamepadAxis.Set(3, ui.ActionScrollY)
+}
+
 var defaultKeyMap *keymap.KeyMap
 
 func init() {
@@ -20,6 +20,11 @@ const (
 	frameTime = (time.Second / time.Nanosecond) / ebiten.FPS * time.Nanosecond
 )
 
+const (
+	// cameraShakeAmplitude is how far the camera shakes unless the player turns it off.
+	cameraShakeAmplitude = 30
+)
+
 const (
 	ignore     = "ignore"
 	left       = "left"
@@ -34,15 +39,17 @@ const (
 	punchV     = "punch vertical"
 	fullscreen = "fullscreen"
 	pause      = "pause"
-	leftClick  = "left click"
 )
 
 const (
-	generalLayer   = iota
-	remapLayer     // Handles key remapping
-	leftClickLayer // Handles UI left clicks
-	uiLayer        // Handles other UI keys
-	playerLayer    // Handles player controls
+	// typingLayer keeps typed keys from later layers while a menu is capturing.
+	typingLayer  = iota
+	generalLayer // Handles pause and fullscreen
+	remapLayer   // Handles key remapping
+	popupLayer   // Handles UI drawn over the current menu
+	menuLayer    // Handles the current menu
+	uiLayer      // Handles other UI keys
+	playerLayer  // Handles player controls
 	numInputLayers
 )
 
@@ -50,10 +57,13 @@ const (
 type Game struct {
 	state         gameStateName
 	states        map[gameStateName]gameState
+	settings      *settings
+	settingsPath  string
 	showDebugInfo bool
 	timeScale     float64
 	lastUpdate    time.Time
 	camera        *camera.Camera
+	viewports     []*camera.Viewport
 	background    *background
 	// inputDisabled       bool
 	canToggleFullscreen bool
@@ -76,9 +86,13 @@ type Game struct {
 	counter     time.Duration
 }
 
-// New creates, initializes, and returns a new Game.
-func New(screenWidth, screenHeight int) *Game {
-	// sprite.AddSheet(asset.Img("sheet"), asset.SheetDesc("sheet"))
+// New creates, initializes, and returns a new Game. The player's settings are loaded from
+// settingsPath and saved back to it when they change.
+func New(screenWidth, screenHeight int, settingsPath string) *Game {
+	s, err := loadSettings(settingsPath)
+	if err != nil {
+		log.Println(err)
+	}
 
 	cam := camera.New(screenWidth, screenHeight)
 	// cam.MaxDist = 100
@@ -89,7 +103,10 @@ func New(screenWidth, screenHeight int) *Game {
 	// cam.MaxSpeed = 600
 	// cam.Ease = geo.EaseInExpo
 
-	cam.Shaker.Amplitude = 30
+	// The intro state chooses the real target once loading is done.
+	cam.Target = fixedCameraTarget{geo.Vec0}
+
+	cam.Shaker.Amplitude = cameraShakeAmplitude
 	cam.Shaker.Duration = 1 * time.Second
 	cam.Shaker.Frequency = 10
 	cam.Shaker.Falloff = geo.EaseOutQuad
@@ -99,11 +116,16 @@ func New(screenWidth, screenHeight int) *Game {
 	bg := newBackground()
 
 	g := &Game{
-		state:         intro,
+		state:         loading,
+		settings:      s,
+		settingsPath:  settingsPath,
 		showDebugInfo: true,
 		timeScale:     1.0,
 		camera:        cam,
-		background:    bg,
+		viewports: []*camera.Viewport{
+			camera.NewViewport(cam, geo.RectWH(float64(screenWidth), float64(screenHeight))),
+		},
+		background: bg,
 
 		keymap: make(keymap.Layers, numInputLayers),
 
@@ -117,30 +139,12 @@ func New(screenWidth, screenHeight int) *Game {
 		test: map[string]*sprite.Desc{},
 	}
 
-	descs, err := sprite.Psd(asset.Psd("test"))
-	if err != nil {
-		log.Fatalf("Adding PSD asset 'test' to collection: %v", err)
-	}
-	for i := range descs {
-		g.test[descs[i].Name] = &descs[i]
-	}
-	g.testSprites = []sprite.Sprite{
-		sprite.Sprite{
-			Desc: g.test["white"],
-		},
-		sprite.Sprite{
-			Desc: g.test["white"],
-			Loop: true,
-		},
-		sprite.Sprite{
-			Desc: g.test["green"],
-		},
-	}
-
 	generalActions := keymap.ButtonHandlerMap{
 		pause: func(down bool) bool {
 			if down && g.canTogglePause {
-				log.Println("pause not implement yet")
+				if p, ok := g.states[g.state].(pauser); ok {
+					p.pause()
+				}
 				g.canTogglePause = false
 			} else if !down {
 				g.canTogglePause = true
@@ -149,7 +153,9 @@ func New(screenWidth, screenHeight int) *Game {
 		},
 		fullscreen: func(down bool) bool {
 			if down && g.canToggleFullscreen {
-				ebiten.SetFullscreen(!ebiten.IsFullscreen())
+				g.settings.Fullscreen = !g.settings.Fullscreen
+				ebiten.SetFullscreen(g.settings.Fullscreen)
+				g.saveSettings()
 				g.canToggleFullscreen = false
 			} else if !down {
 				g.canToggleFullscreen = true
@@ -158,6 +164,8 @@ func New(screenWidth, screenHeight int) *Game {
 		},
 	}
 
+	g.setupTypingKeymap()
+
 	g.keymap[generalLayer] = keymap.New(generalActions, nil)
 	g.keymap[generalLayer].KeyMouse.Set(button.FromKey(ebiten.KeyEscape), pause)
 	g.keymap[generalLayer].KeyMouse.Set(button.FromKey(ebiten.KeyF11), fullscreen)
@@ -178,11 +186,62 @@ func New(screenWidth, screenHeight int) *Game {
 	}
 	g.keymap[playerLayer] = keymap.New(playerActions, playerAxisActions)
 	setDefaultKeyMap(g.keymap[playerLayer])
+	s.Controls.apply(g.keymap[playerLayer])
+	g.applySettings()
+	asset.WatchFile(settingsPath, g.reloadSettings)
+
+	batch := &asset.Batch{}
+	batch.Add("psd test", func() error {
+		_, err := asset.Psd("test")
+		return err
+	})
+	batch.Add("menu font", func() error {
+		_, err := menuFace(s)(menuFont, menuFontSize)
+		return err
+	})
+	uiNames := []string{mainMenuUI, pauseMenuUI, settingsMenuUI, axisMenuUI}
+	for _, tab := range settingsTabs {
+		uiNames = append(uiNames, settingsTabUI(tab))
+	}
+	for _, name := range uiNames {
+		name := name
+		batch.Add("ui "+name, func() error {
+			_, err := asset.UI(name)
+			return err
+		})
+	}
+	for _, name := range menuThemes {
+		name := name
+		batch.Add("theme "+name, func() error {
+			_, err := asset.Theme(name)
+			return err
+		})
+	}
+	// States that need assets are created once they're loaded.
+	onLoaded := func() error {
+		menu, err := newMainMenu(p, screenHeight, screenWidth, cam, bg, g.keymap, s)
+		if err != nil {
+			return err
+		}
+		g.states[mainMenu] = menu
+		pauseState, err := newPauseMenu(p, screenHeight, screenWidth, bg, g.keymap, s)
+		if err != nil {
+			return err
+		}
+		g.states[pauseMenu] = pauseState
+		settingsState, err := newSettingsMenu(p, screenHeight, screenWidth, bg, g.keymap, s,
+			g.applySettings, g.saveSettings)
+		if err != nil {
+			return err
+		}
+		g.states[settingsMenu] = settingsState
+		return g.loadTest()
+	}
 
 	g.states = map[gameStateName]gameState{
-		intro:    newIntroState(p, screenHeight, cam, bg),
-		mainMenu: newMainMenu(p, screenHeight, screenWidth, cam, bg, g.keymap),
-		play:     newPlayState(p, screenHeight, cam, bg),
+		loading: newLoadingState(batch, onLoaded, screenWidth, screenHeight),
+		intro:   newIntroState(p, screenHeight, cam, bg),
+		play:    newPlayState(p, screenHeight, cam, bg),
 	}
 
 	// // This keymap layer is for disabling all input
@@ -204,11 +263,47 @@ func New(screenWidth, screenHeight int) *Game {
 	return g
 }
 
+// setupTypingKeymap binds every key in the typing layer to a handler that stops the key
+// while the current state is capturing keyboard input, so that e.g. typing into a text
+// input doesn't pause the game or move the player.
+func (g *Game) setupTypingKeymap() {
+	handlers := keymap.ButtonHandlerMap{}
+	for key := ebiten.Key0; key <= ebiten.KeyMax; key++ {
+		action := keymap.Action(fmt.Sprintf("type%d", key))
+		handlers[action] = g.typingHandler(button.FromKey(key))
+	}
+	g.keymap[typingLayer] = keymap.New(handlers, nil)
+	for key := ebiten.Key0; key <= ebiten.KeyMax; key++ {
+		action := keymap.Action(fmt.Sprintf("type%d", key))
+		g.keymap[typingLayer].KeyMouse.Set(button.FromKey(key), action)
+	}
+}
+
+func (g *Game) typingHandler(key button.KeyMouse) keymap.ButtonHandler {
+	return func(down bool) bool {
+		c, ok := g.states[g.state].(capturer)
+		if !down || !ok || !c.capturing() {
+			return false
+		}
+		// The menus' own keys, e.g. confirm, still reach them. The ui.Root that's capturing
+		// stops them from going any further.
+		for _, layer := range []int{popupLayer, menuLayer} {
+			if km := g.keymap[layer]; km != nil {
+				if _, ok := km.KeyMouse.GetAction(key); ok {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
 // Update the Game by simulating the state by one frame.
 func (g *Game) Update() {
 	updateStart := time.Now()
 	dt := g.dt(updateStart)
 
+	asset.Poll()
 	g.keymap.Update()
 
 	s := g.states[g.state]
@@ -234,7 +329,9 @@ func (g *Game) Update() {
 		g.testSprites[i].Update(dt)
 	}
 
-	g.camera.Update(dt)
+	for _, vp := range g.viewports {
+		vp.Camera.Update(dt)
+	}
 
 	g.handleCollisions()
 
@@ -251,19 +348,16 @@ func (g *Game) Update() {
 func (g *Game) Draw(dst *ebiten.Image) {
 	drawStart := time.Now()
 
-	g.states[g.state].draw(dst, g.camera)
-
-	opts := ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(geo.VecXY(50, 100).Minus(g.testSprites[0].Points("anchor")[0]).XY())
-	g.testSprites[0].Draw(dst, &opts)
-
-	opts.GeoM.Reset()
-	opts.GeoM.Translate(geo.VecXY(50, 120).Minus(g.testSprites[1].Points("anchor")[0]).XY())
-	g.testSprites[1].Draw(dst, &opts)
+	for _, vp := range g.viewports {
+		vp.Draw(dst, g.states[g.state].draw)
+	}
 
-	opts.GeoM.Reset()
-	opts.GeoM.Translate(geo.VecXY(50, 140).Minus(g.testSprites[2].Points("anchor")[0]).XY())
-	g.testSprites[2].Draw(dst, &opts)
+	// The test sprites are made by loadTest once the loading state is done.
+	if len(g.testSprites) == 3 {
+		g.testSprites[0].Draw(dst, sprite.Transform{Pos: geo.VecXY(50, 100), Pivot: "anchor"})
+		g.testSprites[1].Draw(dst, sprite.Transform{Pos: geo.VecXY(50, 120), Pivot: "anchor"})
+		g.testSprites[2].Draw(dst, sprite.Transform{Pos: geo.VecXY(50, 140), Pivot: "anchor"})
+	}
 
 	if g.showDebugInfo {
 		drawTime := time.Since(drawStart)
@@ -278,6 +372,102 @@ func (g *Game) Draw(dst *ebiten.Image) {
 	}
 }
 
+// loadTest sets up the test sprites. The loading state has already cached the PSD so this
+// doesn't have to wait on anything.
+func (g *Game) loadTest() error {
+	descs, err := asset.Psd("test")
+	if err != nil {
+		return err
+	}
+	for i := range descs {
+		g.test[descs[i].Name] = &descs[i]
+	}
+	asset.WatchPsd("test", g.reloadTest)
+	g.testSprites = []sprite.Sprite{
+		sprite.Sprite{
+			Desc: g.test["white"],
+		},
+		sprite.Sprite{
+			Desc: g.test["white"],
+			Loop: true,
+		},
+		sprite.Sprite{
+			Desc: g.test["green"],
+		},
+	}
+	return nil
+}
+
+// reloadTest replaces the test Descs in place so that the Sprites using them pick up the
+// changes.
+func (g *Game) reloadTest() error {
+	descs, err := asset.Psd("test")
+	if err != nil {
+		return err
+	}
+	for i := range descs {
+		if d, ok := g.test[descs[i].Name]; ok {
+			*d = descs[i]
+		} else {
+			g.test[descs[i].Name] = &descs[i]
+		}
+	}
+	// The number of frames may have changed so start over to be safe.
+	for i := range g.testSprites {
+		g.testSprites[i].Start()
+	}
+	return nil
+}
+
+// ScreenScale returns the screen scale that the player chose, for starting the game with.
+func (g *Game) ScreenScale() float64 {
+	return g.settings.Scale
+}
+
+// applySettings makes the game match the settings.
+func (g *Game) applySettings() {
+	s := g.settings
+	ebiten.SetFullscreen(s.Fullscreen)
+	ebiten.SetScreenScale(s.Scale)
+	ebiten.SetVsyncEnabled(s.VSync)
+	g.timeScale = s.GameSpeed
+	g.showDebugInfo = s.ShowDebugInfo
+	g.player.deadzone = s.Deadzone
+	g.camera.Shaker.Amplitude = 0
+	if s.CameraShake {
+		g.camera.Shaker.Amplitude = cameraShakeAmplitude
+	}
+}
+
+// reloadSettings loads the settings file again and applies it, e.g. after it was edited by
+// hand. The game's own saves are reloaded too, which changes nothing.
+func (g *Game) reloadSettings() error {
+	s, err := loadSettings(g.settingsPath)
+	if err != nil {
+		return err
+	}
+	// The menus share the settings so they're replaced in place.
+	*g.settings = *s
+	// The settings menu mirrors the player's controls in the ui layer.
+	for _, layer := range []int{playerLayer, uiLayer} {
+		if km := g.keymap[layer]; km != nil {
+			setDefaultKeyMap(km)
+			s.Controls.apply(km)
+		}
+	}
+	g.applySettings()
+	return nil
+}
+
+// saveSettings saves the settings, including the player's current controls, to the
+// settings file.
+func (g *Game) saveSettings() {
+	g.settings.Controls = controlsOf(g.keymap[playerLayer])
+	if err := g.settings.save(g.settingsPath); err != nil {
+		log.Println(err)
+	}
+}
+
 func (g *Game) dt(now time.Time) time.Duration {
 	ns := now.Sub(g.lastUpdate).Nanoseconds()
 	scaled := float64(ns) * g.timeScale
@@ -10,9 +10,12 @@ import (
 type gameStateName int
 
 const (
-	intro gameStateName = iota
+	loading gameStateName = iota
+	intro
 	mainMenu
 	play
+	pauseMenu
+	settingsMenu
 )
 
 type gameState interface {
@@ -22,3 +25,14 @@ type gameState interface {
 	update(dt time.Duration)
 	draw(dst *ebiten.Image, cam *camera.Camera)
 }
+
+// pauser is a gameState that does something when the pause action is pressed.
+type pauser interface {
+	pause()
+}
+
+// capturer is a gameState whose menus can take all keyboard input, e.g. while the player
+// types into a ui.TextInput.
+type capturer interface {
+	capturing() bool
+}
@@ -9,22 +9,25 @@ import (
 )
 
 type introState struct {
-	p  *player
-	bg *background
+	p            *player
+	screenHeight int
+	cam          *camera.Camera
+	bg           *background
 }
 
 func newIntroState(p *player, screenHeight int, cam *camera.Camera, bg *background) *introState {
-	p.SetPos(geo.Vec0)
-	cam.Target = fixedCameraTarget{geo.VecXY(0, -float64(screenHeight)*0.4)}
-	p.awaken()
 	return &introState{
-		p:  p,
-		bg: bg,
+		p:            p,
+		screenHeight: screenHeight,
+		cam:          cam,
+		bg:           bg,
 	}
 }
 
 func (i *introState) begin(previousState gameStateName) {
-	// Since introState is the first state, begin is not called
+	i.p.SetPos(geo.Vec0)
+	i.cam.Target = fixedCameraTarget{geo.VecXY(0, -float64(i.screenHeight)*0.4)}
+	i.p.awaken()
 }
 
 func (i *introState) end() {
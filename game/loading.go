@@ -0,0 +1,104 @@
+package game
+
+import (
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/Bredgren/game1/game/asset"
+	"github.com/Bredgren/game1/game/camera"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+const (
+	loadingBarWidth  = 200
+	loadingBarHeight = 10
+)
+
+// loadingState loads assets in the background while showing the progress. If loading
+// fails the errors are shown and the game doesn't continue.
+type loadingState struct {
+	batch        *asset.Batch
+	onLoaded     func() error
+	loaded       bool
+	err          error
+	screenWidth  int
+	screenHeight int
+}
+
+// newLoadingState starts loading batch. onLoaded is called from update once the batch
+// has finished without errors, it should collect the loaded assets.
+func newLoadingState(batch *asset.Batch, onLoaded func() error, screenWidth, screenHeight int) *loadingState {
+	batch.Start()
+	return &loadingState{
+		batch:        batch,
+		onLoaded:     onLoaded,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}
+}
+
+func (l *loadingState) begin(previousState gameStateName) {
+	// Since loadingState is the first state, begin is not called
+}
+
+func (l *loadingState) end() {
+
+}
+
+func (l *loadingState) nextState() gameStateName {
+	if l.loaded {
+		return intro
+	}
+	return loading
+}
+
+func (l *loadingState) update(dt time.Duration) {
+	if l.loaded || l.err != nil || !l.batch.Done() {
+		return
+	}
+	if l.err = l.batch.Err(); l.err != nil {
+		return
+	}
+	if l.err = l.onLoaded(); l.err != nil {
+		return
+	}
+	l.loaded = true
+}
+
+func (l *loadingState) draw(dst *ebiten.Image, cam *camera.Camera) {
+	dst.Fill(color.Black)
+
+	if l.err != nil {
+		face := basicfont.Face7x13
+		y := face.Metrics().Ascent.Ceil() + 20
+		lines := append([]string{"Failed to load assets:"}, errorLines(l.err)...)
+		for _, line := range lines {
+			text.Draw(dst, line, face, 10, y, color.NRGBA{200, 0, 0, 255})
+			y += face.Metrics().Height.Ceil()
+		}
+		return
+	}
+
+	finished, total := l.batch.Progress()
+	progress := 1.0
+	if total > 0 {
+		progress = float64(finished) / float64(total)
+	}
+	x := float64(l.screenWidth-loadingBarWidth) / 2
+	y := float64(l.screenHeight-loadingBarHeight) / 2
+	ebitenutil.DrawRect(dst, x, y, loadingBarWidth, loadingBarHeight, color.NRGBA{100, 100, 100, 255})
+	ebitenutil.DrawRect(dst, x, y, loadingBarWidth*progress, loadingBarHeight, color.NRGBA{200, 200, 200, 255})
+}
+
+// errorLines splits an error's message into indented lines for display.
+func errorLines(err error) []string {
+	var lines []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
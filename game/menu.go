@@ -0,0 +1,125 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/image/font"
+
+	"github.com/Bredgren/game1/game/asset"
+	"github.com/Bredgren/game1/game/keymap"
+	"github.com/Bredgren/game1/game/tween"
+	"github.com/Bredgren/game1/game/ui"
+	"github.com/Bredgren/geo"
+)
+
+const (
+	// menuMargin is the space above the menus and twice the space between the settings menu
+	// and the axis menu.
+	menuMargin = 20
+)
+
+const (
+	// menuSlide is how far menus slide down as they appear.
+	menuSlide    = 30
+	menuShowTime = 300 * time.Millisecond
+	menuHideTime = 200 * time.Millisecond
+)
+
+const (
+	// menuFont is the name of the font used for menu text, see asset.Font.
+	menuFont = "goregular"
+	// menuFontSize is the size of menu text in game pixels, see menuFace.
+	menuFontSize = 11
+)
+
+// menuThemes are the ui themes that the player can choose from, see asset.Theme. The first
+// is the default.
+var menuThemes = []string{"default", "highcontrast"}
+
+// newMenuRoot returns a hidden Root for a menu at the top center of the screen. Its
+// Element is the returned Transform, which the menu's tree goes in, and it slides down and
+// fades in when it's shown and does the reverse when it's hidden. Showing or hiding it part
+// way through the other turns around from where it is.
+func newMenuRoot(screenWidth, screenHeight int) (*ui.Root, *ui.Transform) {
+	t := ui.NewTransform(nil)
+	r := &ui.Root{
+		Element: t,
+		Bounds:  geo.RectWH(float64(screenWidth), float64(screenHeight)),
+		Anchor: ui.Anchor{
+			Src:    geo.VecXY(0.5, 0),
+			Dst:    geo.VecXY(0.5, 0),
+			Offset: geo.VecXY(0, menuMargin),
+		},
+		Hidden: true,
+	}
+	r.OnShow = func() {
+		offset, alpha := geo.VecXY(0, -menuSlide), 0.0
+		if r.Hiding() {
+			offset, alpha = t.Offset, t.Alpha
+		}
+		r.Tweens.Add(
+			tween.Vec(&t.Offset, offset, geo.Vec0, menuShowTime, geo.EaseOutQuad),
+			tween.Float(&t.Alpha, alpha, 1, menuShowTime, geo.EaseOutQuad),
+		)
+	}
+	r.OnHide = func() {
+		r.Tweens.Add(
+			tween.Vec(&t.Offset, t.Offset, geo.VecXY(0, -menuSlide), menuHideTime, geo.EaseInQuad),
+			tween.Float(&t.Alpha, t.Alpha, 0, menuHideTime, geo.EaseInQuad),
+		)
+	}
+	return r, t
+}
+
+// menuFace returns a function that loads a face like asset.Face does, for the ui bindings
+// of menus. Sizes are in game pixels and are adjusted for the screen scale in s, see
+// scaledFontSize, so a different face is loaded and cached for each scale. Menus must be
+// rebuilt when the scale changes to get the new faces.
+func menuFace(s *settings) func(name string, size float64) (font.Face, error) {
+	return func(name string, size float64) (font.Face, error) {
+		return asset.Face(name, scaledFontSize(size, s.Scale))
+	}
+}
+
+// scaledFontSize returns the font size, in game pixels, that is closest to size while being a
+// whole number of screen pixels when the screen is scaled by scale.
+func scaledFontSize(size, scale float64) float64 {
+	if scale <= 0 {
+		return size
+	}
+	// math.Round needs Go 1.10, which is newer than the rest of the tree.
+	return math.Max(1, math.Floor(size*scale+0.5)) / scale
+}
+
+// newMenuKeyMap returns r's KeyMap with the default UI bindings.
+func newMenuKeyMap(r *ui.Root) *keymap.KeyMap {
+	km := r.KeyMap()
+	setDefaultUIKeyMap(km)
+	return km
+}
+
+// buildUI builds the named ui definition, see asset.UI.
+func buildUI(name string, b ui.Bindings) (*ui.Tree, error) {
+	def, err := asset.UI(name)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := def.Build(b)
+	if err != nil {
+		return nil, fmt.Errorf("build ui '%s': %v", name, err)
+	}
+	return tree, nil
+}
+
+// watchMenu calls reload when any of the named ui definitions, or any of the menuThemes,
+// change.
+func watchMenu(reload func() error, uiNames ...string) {
+	for _, name := range uiNames {
+		asset.WatchUI(name, reload)
+	}
+	for _, name := range menuThemes {
+		asset.WatchTheme(name, reload)
+	}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bredgren/game1/game/asset"
+	"github.com/Bredgren/game1/game/camera"
+	"github.com/Bredgren/game1/game/keymap"
+	"github.com/Bredgren/game1/game/ui"
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	// pauseMenuUI is the ui definition of the pause menu, see asset.UI.
+	pauseMenuUI = "pausemenu"
+)
+
+// pauseMenuState stops the game while it's open and is where the player opens the settings
+// during play.
+type pauseMenuState struct {
+	p        *player
+	bg       *background
+	keymap   keymap.Layers
+	settings *settings
+	theme    *ui.Theme
+	// themeName is the name of theme, and scale the screen scale the faces were loaded
+	// for, to know when the player has chosen others.
+	themeName string
+	scale     float64
+
+	root      *ui.Root
+	tree      *ui.Tree
+	transform *ui.Transform
+	keys      *keymap.KeyMap
+
+	// next is the state to go to once the menu is hidden.
+	next gameStateName
+}
+
+func newPauseMenu(p *player, screenHeight, screenWidth int, bg *background, km keymap.Layers,
+	s *settings) (*pauseMenuState, error) {
+	m := &pauseMenuState{
+		p:        p,
+		bg:       bg,
+		keymap:   km,
+		settings: s,
+	}
+	m.root, m.transform = newMenuRoot(screenWidth, screenHeight)
+	m.keys = newMenuKeyMap(m.root)
+
+	if err := m.loadMenu(); err != nil {
+		return nil, err
+	}
+	watchMenu(m.loadMenu, pauseMenuUI)
+
+	return m, nil
+}
+
+// loadMenu builds the menu from its ui definition with the theme and screen scale in the
+// settings.
+func (m *pauseMenuState) loadMenu() error {
+	theme, err := asset.Theme(m.settings.Theme)
+	if err != nil {
+		return err
+	}
+	m.theme = theme
+	tree, err := buildUI(pauseMenuUI, m.bindings())
+	if err != nil {
+		return err
+	}
+	m.themeName = m.settings.Theme
+	m.scale = m.settings.Scale
+	m.tree = tree
+	m.transform.Element = tree.Element
+	return nil
+}
+
+// bindings connects the names used in the menu's ui definition to the menu.
+func (m *pauseMenuState) bindings() ui.Bindings {
+	return ui.Bindings{
+		Callbacks: map[string]func(string){
+			"resume":   func(string) { m.close(play) },
+			"settings": func(string) { m.close(settingsMenu) },
+		},
+		Face:  menuFace(m.settings),
+		Image: asset.Img,
+		Theme: m.theme,
+	}
+}
+
+// close hides the menu and then goes to the next state.
+func (m *pauseMenuState) close(next gameStateName) {
+	m.next = next
+	m.root.Hide()
+}
+
+// pause resumes the game, so that the pause button toggles the menu.
+func (m *pauseMenuState) pause() {
+	m.close(play)
+}
+
+func (m *pauseMenuState) begin(previousState gameStateName) {
+	m.keymap[menuLayer] = m.keys
+	if m.themeName != m.settings.Theme || m.scale != m.settings.Scale {
+		if err := m.loadMenu(); err != nil {
+			log.Println(err)
+		}
+	}
+	m.next = pauseMenu
+	m.root.Show()
+}
+
+// capturing returns true while the menu is taking all keyboard input.
+func (m *pauseMenuState) capturing() bool {
+	return m.root.Capturing()
+}
+
+func (m *pauseMenuState) end() {
+	m.root.Hidden = true
+}
+
+func (m *pauseMenuState) nextState() gameStateName {
+	// Wait for the menu to finish hiding so that it doesn't disappear suddenly.
+	if m.root.Hidden {
+		return m.next
+	}
+	return pauseMenu
+}
+
+func (m *pauseMenuState) update(dt time.Duration) {
+	m.root.Update(dt)
+	m.tree.Refresh()
+}
+
+func (m *pauseMenuState) draw(dst *ebiten.Image, cam *camera.Camera) {
+	m.bg.Draw(dst, cam)
+	m.p.draw(dst, cam)
+	m.root.Draw(dst)
+}
@@ -3,9 +3,11 @@ package game
 import (
 	"image/color"
 	"log"
+	"math"
 	"time"
 
 	"github.com/Bredgren/game1/game/camera"
+	"github.com/Bredgren/game1/game/sprite"
 	"github.com/Bredgren/geo"
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/ebitenutil"
@@ -81,6 +83,8 @@ type player struct {
 	punchAxis        geo.Vec
 	punchWithGamepad bool
 	launch           bool // Launch button is down
+	// deadzone is how far the gamepad axes must be pushed before they do anything.
+	deadzone float64
 
 	canJump   bool
 	isJumping bool
@@ -281,12 +285,11 @@ func (p *player) updateHitboxes() {
 }
 
 func (p *player) draw(dst *ebiten.Image, cam *camera.Camera) {
-	// pos := cam.ScreenCoords(p.pos)
-	geom := ebiten.GeoM{}
-
-	// size := p.currentSprite.Size()
-	// bounds := geo.RectWH(size.XY())
-	// bounds.SetBottomMid(pos.XY())
+	// The sprites' "anchor" point is at the player's feet, which is where pos is.
+	t := sprite.Transform{
+		Pos:   cam.ScreenCoords(p.pos),
+		Pivot: "anchor",
+	}
 
 	switch p.state {
 	case awaken:
@@ -295,42 +298,32 @@ func (p *player) draw(dst *ebiten.Image, cam *camera.Camera) {
 		// case playerMove:
 		p.flipDir = p.vel.X < 0
 		// case playerPunch:
+		// 	// Rotate around the middle of the sprite instead of its feet.
+		// 	t.Pos = p.currentSprite.TransformedPoints("center", t)[0]
+		// 	t.Pivot = "center"
 		// 	mPos := geo.VecXYi(ebiten.CursorPosition())
-		// 	pos = geo.VecXY(bounds.Mid())
-		//
 		// 	if p.punchWithGamepad {
-		// 		mPos = pos.Plus(p.punchAxis)
+		// 		mPos = t.Pos.Plus(p.punchAxis)
 		// 	}
 		//
-		// 	p.flipDir = mPos.X < pos.X
-		// 	angle := mPos.Minus(pos).Angle()
+		// 	p.flipDir = mPos.X < t.Pos.X
+		// 	t.Rotation = -mPos.Minus(t.Pos).Angle()
 		// 	if p.flipDir {
-		// 		angle += math.Pi
-		// 		angle *= -1
+		// 		t.Rotation -= math.Pi
 		// 	}
-		// 	geom.Translate(-size.X/2, -size.Y/2)
-		// 	geom.Rotate(-angle)
-		// 	geom.Translate(size.X/2, size.Y/2)
 		// case charge:
 		// 	mPos := geo.VecXYi(ebiten.CursorPosition())
 		// 	if p.punchAxis.Len() != 0 {
-		// 		mPos = pos.Plus(p.punchAxis)
+		// 		mPos = t.Pos.Plus(p.punchAxis)
 		// 	}
 		//
 		// 	p.flipDir = false
-		// 	angle := mPos.Minus(pos).AngleFrom(geo.VecXY(0, -1))
-		//
-		// 	geom.Translate(-size.X/2, -size.Y)
-		// 	geom.Rotate(-angle)
-		// 	geom.Translate(size.X/2, size.Y)
+		// 	t.Rotation = -mPos.Minus(t.Pos).AngleFrom(geo.VecXY(0, -1))
 		// case playerLaunch:
 	}
 
-	if p.flipDir {
-		geom.Scale(-1, 1)
-		// geom.Translate(size.X, 0)
-	}
-	// p.currentSprite.Draw(dst, pos, geom)
+	t.FlipX = p.flipDir
+	// p.currentSprite.Draw(dst, t)
 
 	// debug draw hitboxes
 	if p.attackHitbox.Active {
@@ -407,7 +400,7 @@ func (p *player) handleRight(down bool) bool {
 }
 
 func (p *player) handleMove(val float64) bool {
-	p.move = val
+	p.move = applyDeadzone(val, p.deadzone)
 	return false
 }
 
@@ -422,12 +415,12 @@ func (p *player) handlePunch(down bool) bool {
 }
 
 func (p *player) handlePunchH(val float64) bool {
-	p.punchAxis.X = val
+	p.punchAxis.X = applyDeadzone(val, p.deadzone)
 	return false
 }
 
 func (p *player) handlePunchV(val float64) bool {
-	p.punchAxis.Y = -val
+	p.punchAxis.Y = -applyDeadzone(val, p.deadzone)
 	return false
 }
 
@@ -436,6 +429,15 @@ func (p *player) handleLaunch(down bool) bool {
 	return false
 }
 
+// applyDeadzone returns 0 for axis values within deadzone of 0. Values outside of it are
+// scaled so that pushing the axis all the way still gives 1.
+func applyDeadzone(val, deadzone float64) float64 {
+	if math.Abs(val) <= deadzone {
+		return 0
+	}
+	return math.Copysign((math.Abs(val)-deadzone)/(1-deadzone), val)
+}
+
 func (p *player) hitboxes() []*hitbox {
 	return []*hitbox{&p.coreHitbox, &p.attackHitbox}
 }
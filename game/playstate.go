@@ -13,6 +13,7 @@ type playState struct {
 	target       *dynamicCameraTarget
 	bg           *background
 	screenHeight int
+	paused       bool
 }
 
 func newPlayState(p *player, screenHeight int, cam *camera.Camera, bg *background) *playState {
@@ -27,12 +28,21 @@ func newPlayState(p *player, screenHeight int, cam *camera.Camera, bg *backgroun
 
 func (p *playState) begin(previousState gameStateName) {
 	p.cam.Target = p.target
+	p.paused = false
 }
 
 func (p *playState) end() {
 }
 
+// pause opens the pause menu.
+func (p *playState) pause() {
+	p.paused = true
+}
+
 func (p *playState) nextState() gameStateName {
+	if p.paused {
+		return pauseMenu
+	}
 	return play
 }
 
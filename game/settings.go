@@ -0,0 +1,196 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Bredgren/game1/game/keymap"
+	"github.com/Bredgren/game1/game/keymap/button"
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	// maxDeadzone is the largest settings.Deadzone. The slider in the controls tab goes up
+	// to it too.
+	maxDeadzone = 0.5
+)
+
+var (
+	// screenScales are the choices for settings.Scale.
+	screenScales = []float64{1, 1.5, 2, 3}
+	// gameSpeeds are the choices for settings.GameSpeed.
+	gameSpeeds = []float64{0.5, 0.75, 1}
+)
+
+// settings are the choices the player makes in the settings menu. They're saved to the
+// settings file when the menu closes and applied when the game starts.
+type settings struct {
+	Fullscreen bool `json:"fullscreen"`
+	// Scale is the size of a game pixel in screen pixels, one of screenScales.
+	Scale float64 `json:"scale"`
+	VSync bool    `json:"vsync"`
+	// Theme is the name of one of the menuThemes.
+	Theme string `json:"theme"`
+
+	// The volumes are between 0 and 1. Nothing plays audio yet, they're kept for when
+	// something does.
+	MasterVolume  float64 `json:"masterVolume"`
+	MusicVolume   float64 `json:"musicVolume"`
+	EffectsVolume float64 `json:"effectsVolume"`
+	Muted         bool    `json:"muted"`
+
+	// GameSpeed scales how much time passes each frame, one of gameSpeeds.
+	GameSpeed     float64 `json:"gameSpeed"`
+	CameraShake   bool    `json:"cameraShake"`
+	ShowDebugInfo bool    `json:"showDebugInfo"`
+
+	// Deadzone is how far the gamepad axes must be pushed to move the player, between 0 and
+	// maxDeadzone.
+	Deadzone float64  `json:"deadzone"`
+	Controls controls `json:"controls"`
+}
+
+// controls are the player's bindings. They're empty until the settings are first saved,
+// in which case the defaults are used.
+type controls struct {
+	Keys    map[keymap.Action]button.KeyMouse      `json:"keys"`
+	Buttons map[keymap.Action]ebiten.GamepadButton `json:"buttons"`
+	Axes    map[keymap.Action]int                  `json:"axes"`
+}
+
+func defaultSettings() *settings {
+	return &settings{
+		Scale:         2,
+		VSync:         true,
+		Theme:         menuThemes[0],
+		MasterVolume:  1,
+		MusicVolume:   1,
+		EffectsVolume: 1,
+		GameSpeed:     1,
+		CameraShake:   true,
+		ShowDebugInfo: true,
+		Deadzone:      0.15,
+	}
+}
+
+// loadSettings reads the settings file at path. Settings that aren't in the file, or all of
+// them if there is no file, are the defaults, as are choices that are no longer available.
+func loadSettings(path string) (*settings, error) {
+	s := defaultSettings()
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("load settings '%s': %v", path, err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return defaultSettings(), fmt.Errorf("load settings '%s': %v", path, err)
+	}
+
+	def := defaultSettings()
+	if !hasString(menuThemes, s.Theme) {
+		s.Theme = def.Theme
+	}
+	if !hasFloat(screenScales, s.Scale) {
+		s.Scale = def.Scale
+	}
+	if !hasFloat(gameSpeeds, s.GameSpeed) {
+		s.GameSpeed = def.GameSpeed
+	}
+	for _, v := range []*float64{&s.MasterVolume, &s.MusicVolume, &s.EffectsVolume} {
+		*v = math.Max(0, math.Min(1, *v))
+	}
+	s.Deadzone = math.Max(0, math.Min(maxDeadzone, s.Deadzone))
+	return s, nil
+}
+
+// save writes the settings to path.
+func (s *settings) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save settings '%s': %v", path, err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("save settings '%s': %v", path, err)
+	}
+	return nil
+}
+
+// controlsOf returns the bindings in km.
+func controlsOf(km *keymap.KeyMap) controls {
+	c := controls{
+		Keys:    map[keymap.Action]button.KeyMouse{},
+		Buttons: map[keymap.Action]ebiten.GamepadButton{},
+		Axes:    map[keymap.Action]int{},
+	}
+	for _, a := range km.KeyMouse.Actions() {
+		c.Keys[a], _ = km.KeyMouse.GetButton(a)
+	}
+	for _, a := range km.GamepadBtn.Actions() {
+		c.Buttons[a], _ = km.GamepadBtn.GetButton(a)
+	}
+	for _, a := range km.GamepadAxis.Actions() {
+		c.Axes[a], _ = km.GamepadAxis.GetAxis(a)
+	}
+	return c
+}
+
+// apply replaces the bindings in km with c. It does nothing if c is empty so that km keeps
+// its defaults.
+func (c controls) apply(km *keymap.KeyMap) {
+	if len(c.Keys) == 0 && len(c.Buttons) == 0 && len(c.Axes) == 0 {
+		return
+	}
+	clearKeyMap(km)
+	for a, b := range c.Keys {
+		km.KeyMouse.Set(b, a)
+	}
+	for a, b := range c.Buttons {
+		km.GamepadBtn.Set(b, a)
+	}
+	for a, axis := range c.Axes {
+		km.GamepadAxis.Set(axis, a)
+	}
+}
+
+// multiplierLabel returns the label of a multiplier, e.g. "1.5x" for 1.5.
+func multiplierLabel(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + "x"
+}
+
+func multiplierLabels(values []float64) []string {
+	labels := make([]string, len(values))
+	for i, v := range values {
+		labels[i] = multiplierLabel(v)
+	}
+	return labels
+}
+
+// parseMultiplier returns the value of a label from multiplierLabel.
+func parseMultiplier(label string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(label, "x"), 64)
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFloat(list []float64, f float64) bool {
+	for _, item := range list {
+		if item == f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,690 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Bredgren/game1/game/asset"
+	"github.com/Bredgren/game1/game/camera"
+	"github.com/Bredgren/game1/game/keymap"
+	"github.com/Bredgren/game1/game/keymap/button"
+	"github.com/Bredgren/game1/game/tween"
+	"github.com/Bredgren/game1/game/ui"
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	axisPopupScale = 0.8
+	axisPopupTime  = 150 * time.Millisecond
+)
+
+const (
+	// settingsMenuUI is the ui definition of the settings menu, see asset.UI. The current
+	// tab's page goes in its vertical container with the ID "page".
+	settingsMenuUI = "settingsmenu"
+	// axisMenuUI is shown next to the settings menu while choosing an axis to remap.
+	axisMenuUI = "axismenu"
+)
+
+// settingsTabs are the tabs of the settings menu. The first is shown when the game starts.
+var settingsTabs = []string{"controls", "video", "audio", "gameplay"}
+
+// settingsTabUI returns the name of the ui definition of a settings tab's page.
+func settingsTabUI(tab string) string {
+	return "settings/" + tab
+}
+
+var (
+	// remapActions are the actions that can be remapped in the controls tab, in the order
+	// they're listed.
+	remapActions = []keymap.Action{
+		left, right, move, jump, punch, punchH, punchV, uppercut, slam, launch,
+	}
+	// generalActions are listed in the controls tab but can't be remapped.
+	generalActions = []keymap.Action{
+		pause, fullscreen,
+	}
+)
+
+// settingsMenuState is where the player changes the settings. It goes back to the state
+// that opened it when it's closed.
+type settingsMenuState struct {
+	p           *player
+	bg          *background
+	screenWidth int
+	keymap      keymap.Layers
+	settings    *settings
+	// onChange applies the settings after the player changes one, and onClose saves them.
+	onChange func()
+	onClose  func()
+	theme    *ui.Theme
+	tab      string
+	previous gameStateName
+	closing  bool
+
+	root      *ui.Root
+	tree      *ui.Tree
+	transform *ui.Transform
+	keys      *keymap.KeyMap
+	// page holds the tree of the current tab.
+	page     *ui.VerticalContainer
+	pageTree *ui.Tree
+
+	remapAction keymap.Action
+	remap       bool
+	remapPrompt string
+	// actionDown holds which actions are pressed so that they're highlighted.
+	actionDown map[keymap.Action]bool
+	// axisValue holds the last value of each axis action.
+	axisValue map[keymap.Action]float64
+
+	axisRoot      *ui.Root
+	axisTree      *ui.Tree
+	axisTransform *ui.Transform
+}
+
+func newSettingsMenu(p *player, screenHeight, screenWidth int, bg *background, km keymap.Layers,
+	s *settings, onChange, onClose func()) (*settingsMenuState, error) {
+	m := &settingsMenuState{
+		p:           p,
+		bg:          bg,
+		screenWidth: screenWidth,
+		keymap:      km,
+		settings:    s,
+		onChange:    onChange,
+		onClose:     onClose,
+		tab:         settingsTabs[0],
+		actionDown:  map[keymap.Action]bool{},
+		axisValue:   map[keymap.Action]float64{},
+
+		// axisRoot's Bounds are set next to the menu when drawing.
+		axisRoot: &ui.Root{
+			Anchor: ui.Anchor{
+				Src:    geo.VecXY(0, 0.5),
+				Dst:    geo.VecXY(0, 0.5),
+				Offset: geo.VecXY(menuMargin/2, 0),
+			},
+			Hidden: true,
+			Modal:  true,
+		},
+		axisTransform: ui.NewTransform(nil),
+	}
+	m.root, m.transform = newMenuRoot(screenWidth, screenHeight)
+	m.keys = newMenuKeyMap(m.root)
+	m.axisRoot.Element = m.axisTransform
+
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	m.setupTransitions()
+	m.setupKeymap()
+
+	uiNames := []string{settingsMenuUI, axisMenuUI}
+	for _, tab := range settingsTabs {
+		uiNames = append(uiNames, settingsTabUI(tab))
+	}
+	watchMenu(m.load, uiNames...)
+
+	return m, nil
+}
+
+// load builds the menu with the theme in the settings. Nothing changes if it fails.
+func (m *settingsMenuState) load() error {
+	theme, err := asset.Theme(m.settings.Theme)
+	if err != nil {
+		return err
+	}
+	old := m.theme
+	m.theme = theme
+	if err := m.build(); err != nil {
+		m.theme = old
+		return err
+	}
+	return nil
+}
+
+// build builds the menu, the current tab's page, and the axis menu if it has been built
+// before.
+func (m *settingsMenuState) build() error {
+	b := m.bindings()
+	tree, err := buildUI(settingsMenuUI, b)
+	if err != nil {
+		return err
+	}
+	page, ok := tree.ByID("page").(*ui.VerticalContainer)
+	if !ok {
+		return fmt.Errorf("build ui '%s': no vertical container with id 'page'", settingsMenuUI)
+	}
+	pageTree, err := buildUI(settingsTabUI(m.tab), b)
+	if err != nil {
+		return err
+	}
+	var axisTree *ui.Tree
+	if m.axisTree != nil {
+		if axisTree, err = buildUI(axisMenuUI, b); err != nil {
+			return err
+		}
+	}
+
+	m.tree = tree
+	m.transform.Element = tree.Element
+	m.page = page
+	m.setPage(pageTree)
+	if axisTree != nil {
+		m.axisTree = axisTree
+		m.axisTransform.Element = axisTree.Element
+	}
+	return nil
+}
+
+// loadAxisMenu builds the axis menu. It lists the axes of the first gamepad so it should
+// be built once the gamepad is known.
+func (m *settingsMenuState) loadAxisMenu() error {
+	tree, err := buildUI(axisMenuUI, m.bindings())
+	if err != nil {
+		return err
+	}
+	m.axisTree = tree
+	m.axisTransform.Element = tree.Element
+	return nil
+}
+
+func (m *settingsMenuState) setPage(tree *ui.Tree) {
+	m.pageTree = tree
+	m.page.Elements = []ui.WeightedDrawer{tree.Element}
+}
+
+// setTab shows another tab's page.
+func (m *settingsMenuState) setTab(tab string) {
+	if tab == m.tab {
+		return
+	}
+	tree, err := buildUI(settingsTabUI(tab), m.bindings())
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	m.cancelRemap()
+	m.tab = tab
+	m.setPage(tree)
+}
+
+// setTheme switches to one of the menuThemes and rebuilds the menu with it. The current
+// theme is kept if the new one fails to load.
+func (m *settingsMenuState) setTheme(name string) {
+	old := m.settings.Theme
+	m.settings.Theme = name
+	if err := m.load(); err != nil {
+		log.Println(err)
+		m.settings.Theme = old
+		return
+	}
+	m.onChange()
+}
+
+// setScale changes the screen scale and rebuilds the menu with faces for it.
+func (m *settingsMenuState) setScale(scale float64) {
+	m.settings.Scale = scale
+	m.onChange()
+	if err := m.load(); err != nil {
+		log.Println(err)
+	}
+}
+
+// bindings connects the names used in the menu's ui definitions to the menu.
+func (m *settingsMenuState) bindings() ui.Bindings {
+	return ui.Bindings{
+		Callbacks: map[string]func(string){
+			"tab":            m.setTab,
+			"back":           func(string) { m.back() },
+			"remap":          m.startRemap,
+			"selectAxis":     m.selectAxis,
+			"restoreDefault": func(string) { m.restoreDefault() },
+		},
+		Texts: map[string]func(string) string{
+			"remapPrompt": func(string) string { return m.remapPrompt },
+			"key": func(action string) string {
+				label, _ := m.keyLabel(keymap.Action(action))
+				return label
+			},
+			"gamepad": func(action string) string {
+				label, _ := m.gamepadLabel(keymap.Action(action))
+				return label
+			},
+			"generalKey": func(action string) string {
+				b, _ := m.keymap[generalLayer].KeyMouse.GetButton(keymap.Action(action))
+				return b.String()
+			},
+			"generalGamepad": func(action string) string {
+				b, _ := m.keymap[generalLayer].GamepadBtn.GetButton(keymap.Action(action))
+				return fmt.Sprintf("Gamepad %d", b)
+			},
+			"axisValue": func(axis string) string {
+				a, _ := strconv.Atoi(axis)
+				return fmt.Sprintf("(%.2f)", ebiten.GamepadAxis(0, a))
+			},
+		},
+		Colors: map[string]func(string) color.Color{
+			"tabColor": func(tab string) color.Color {
+				if tab == m.tab {
+					return m.theme.Color("highlight")
+				}
+				return m.theme.Color("text")
+			},
+			"actionColor": func(action string) color.Color {
+				if m.actionDown[keymap.Action(action)] {
+					return m.theme.Color("highlight")
+				}
+				return m.theme.Color("text")
+			},
+			"keyColor": func(action string) color.Color {
+				_, c := m.keyLabel(keymap.Action(action))
+				return c
+			},
+			"gamepadColor": func(action string) color.Color {
+				_, c := m.gamepadLabel(keymap.Action(action))
+				return c
+			},
+		},
+		Lists: map[string]func() []string{
+			"remapActions":   func() []string { return actionNames(remapActions) },
+			"generalActions": func() []string { return actionNames(generalActions) },
+			"axes": func() []string {
+				var axes []string
+				for axis := 0; axis < ebiten.GamepadAxisNum(0); axis++ {
+					axes = append(axes, strconv.Itoa(axis))
+				}
+				return axes
+			},
+			"themes":     func() []string { return menuThemes },
+			"scales":     func() []string { return multiplierLabels(screenScales) },
+			"gameSpeeds": func() []string { return multiplierLabels(gameSpeeds) },
+		},
+		Numbers: map[string]ui.NumberBinding{
+			"volume": {
+				Get: func(name string) float64 { return *m.volume(name) },
+				Set: func(name string, v float64) {
+					*m.volume(name) = v
+					m.onChange()
+				},
+			},
+			"deadzone": {
+				Get: func(string) float64 { return m.settings.Deadzone },
+				Set: func(_ string, v float64) {
+					m.settings.Deadzone = v
+					m.onChange()
+				},
+			},
+		},
+		Bools: map[string]ui.BoolBinding{
+			"setting": {
+				Get: func(name string) bool { return *m.flag(name) },
+				Set: func(name string, v bool) {
+					*m.flag(name) = v
+					m.onChange()
+				},
+			},
+		},
+		Strings: map[string]ui.StringBinding{
+			"scale": {
+				Get: func(string) string { return multiplierLabel(m.settings.Scale) },
+				Set: func(_, label string) {
+					if v, err := parseMultiplier(label); err == nil {
+						m.setScale(v)
+					}
+				},
+			},
+			"gameSpeed": {
+				Get: func(string) string { return multiplierLabel(m.settings.GameSpeed) },
+				Set: func(_, label string) {
+					if v, err := parseMultiplier(label); err == nil {
+						m.settings.GameSpeed = v
+						m.onChange()
+					}
+				},
+			},
+			"theme": {
+				Get: func(string) string { return m.settings.Theme },
+				Set: func(_, name string) { m.setTheme(name) },
+			},
+		},
+		Face:  menuFace(m.settings),
+		Image: asset.Img,
+		Theme: m.theme,
+	}
+}
+
+// flag returns the bool setting with the given name, as used by checkboxes in the ui
+// definitions.
+func (m *settingsMenuState) flag(name string) *bool {
+	switch name {
+	case "fullscreen":
+		return &m.settings.Fullscreen
+	case "vsync":
+		return &m.settings.VSync
+	case "muted":
+		return &m.settings.Muted
+	case "cameraShake":
+		return &m.settings.CameraShake
+	case "showDebugInfo":
+		return &m.settings.ShowDebugInfo
+	}
+	log.Printf("unknown setting '%s'", name)
+	return new(bool)
+}
+
+// volume returns the volume with the given name, as used by sliders in the ui definitions.
+func (m *settingsMenuState) volume(name string) *float64 {
+	switch name {
+	case "master":
+		return &m.settings.MasterVolume
+	case "music":
+		return &m.settings.MusicVolume
+	case "effects":
+		return &m.settings.EffectsVolume
+	}
+	log.Printf("unknown volume '%s'", name)
+	return new(float64)
+}
+
+func actionNames(actions []keymap.Action) []string {
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = string(a)
+	}
+	return names
+}
+
+// setupTransitions animates the axis menu when it's shown and hidden.
+func (m *settingsMenuState) setupTransitions() {
+	// popup animates how much the popup is shown, from 0 for invisible and shrunk to
+	// axisPopupScale, to 1 for fully shown.
+	popup := func(from, to float64, ease geo.EaseFn) {
+		m.axisRoot.Tweens.Add(tween.New(axisPopupTime, ease, func(t float64) {
+			shown := geo.Lerp(from, to, t)
+			scale := geo.Lerp(axisPopupScale, 1, shown)
+			m.axisTransform.Scale = geo.VecXY(scale, scale)
+			m.axisTransform.Alpha = shown
+		}))
+	}
+	// Alpha is how much the popup is shown, so it's where an interrupted transition got to.
+	m.axisRoot.OnShow = func() {
+		from := 0.0
+		if m.axisRoot.Hiding() {
+			from = m.axisTransform.Alpha
+		}
+		popup(from, 1, geo.EaseOutQuad)
+	}
+	m.axisRoot.OnHide = func() {
+		popup(m.axisTransform.Alpha, 0, geo.EaseInQuad)
+	}
+}
+
+// back stops remapping if the player is in the middle of it, otherwise it closes the menu.
+func (m *settingsMenuState) back() {
+	if m.remap || !m.axisRoot.Hidden {
+		m.cancelRemap()
+		return
+	}
+	m.closing = true
+	m.root.Hide()
+}
+
+// pause goes back, so that the pause button closes the menu.
+func (m *settingsMenuState) pause() {
+	m.back()
+}
+
+// startRemap waits for a new button, or an axis to be chosen, for the action.
+func (m *settingsMenuState) startRemap(action string) {
+	m.remapAction = keymap.Action(action)
+	if _, isAxis := m.keymap[playerLayer].GamepadAxis.GetAxis(m.remapAction); isAxis {
+		m.axisRoot.Show()
+		m.remapPrompt = fmt.Sprintf("Select new axis for '%s'", action)
+		return
+	}
+	m.axisRoot.Hide() // to close the axis window if it's open
+	m.remap = true
+	m.remapPrompt = fmt.Sprintf("Press new key/mouse/gamepad button for '%s'", action)
+}
+
+func (m *settingsMenuState) cancelRemap() {
+	m.remap = false
+	m.remapPrompt = ""
+	m.axisRoot.Hide()
+}
+
+// selectAxis remaps the axis action being remapped to the chosen axis.
+func (m *settingsMenuState) selectAxis(axis string) {
+	a, err := strconv.Atoi(axis)
+	if err != nil {
+		return
+	}
+	m.keymap[playerLayer].GamepadAxis.Set(a, m.remapAction)
+	m.keymap[uiLayer].GamepadAxis.Set(a, m.remapAction)
+	m.axisRoot.Hide()
+	m.remapPrompt = ""
+}
+
+func (m *settingsMenuState) restoreDefault() {
+	setDefaultKeyMap(m.keymap[playerLayer])
+	setDefaultKeyMap(m.keymap[uiLayer])
+	m.onChange()
+}
+
+// keyLabel returns the key or mouse button that is mapped to action and the color to show
+// it in.
+func (m *settingsMenuState) keyLabel(action keymap.Action) (string, color.Color) {
+	if btn, ok := m.keymap[playerLayer].KeyMouse.GetButton(action); ok {
+		return btn.String(), m.theme.Color("text")
+	}
+	if _, valid := defaultKeyMap.KeyMouse.GetButton(action); valid {
+		return "N/A", m.theme.Color("unbound")
+	}
+	return "N/A", m.theme.Color("unavailable")
+}
+
+// gamepadLabel returns the gamepad button or axis that is mapped to action and the color
+// to show it in. Axes include their current value.
+func (m *settingsMenuState) gamepadLabel(action keymap.Action) (string, color.Color) {
+	if btn, ok := m.keymap[playerLayer].GamepadBtn.GetButton(action); ok {
+		return fmt.Sprintf("Gamepad %d", btn), m.theme.Color("text")
+	}
+	if axis, ok := m.keymap[playerLayer].GamepadAxis.GetAxis(action); ok {
+		return fmt.Sprintf("Axis %d (%.2f)", axis, m.axisValue[action]), m.theme.Color("text")
+	}
+	_, validBtn := defaultKeyMap.GamepadBtn.GetButton(action)
+	_, validAxis := defaultKeyMap.GamepadAxis.GetAxis(action)
+	if validBtn || validAxis {
+		return "N/A", m.theme.Color("unbound")
+	}
+	return "N/A", m.theme.Color("unavailable")
+}
+
+func (m *settingsMenuState) setupKeymap() {
+	//// Setup remap layer
+	// Button handlers
+	remapHandlers := keymap.ButtonHandlerMap{}
+	for key := ebiten.Key0; key <= ebiten.KeyMax; key++ {
+		action := keymap.Action(fmt.Sprintf("key%d", key))
+		remapHandlers[action] = m.keyRemapHandler(button.FromKey(key))
+	}
+	remapHandlers[keymap.Action("mouse0")] = m.keyRemapHandler(button.FromMouse(ebiten.MouseButtonLeft))
+	remapHandlers[keymap.Action("mouse1")] = m.keyRemapHandler(button.FromMouse(ebiten.MouseButtonMiddle))
+	remapHandlers[keymap.Action("mouse2")] = m.keyRemapHandler(button.FromMouse(ebiten.MouseButtonRight))
+
+	// Gamepad handlers
+	for btn := ebiten.GamepadButton0; btn < ebiten.GamepadButtonMax; btn++ {
+		action := keymap.Action(fmt.Sprintf("btn%d", btn))
+		remapHandlers[action] = m.btnRemapHandler(btn)
+	}
+
+	m.keymap[remapLayer] = keymap.New(remapHandlers, nil)
+
+	// Button actions
+	for key := ebiten.Key0; key <= ebiten.KeyMax; key++ {
+		action := keymap.Action(fmt.Sprintf("key%d", key))
+		m.keymap[remapLayer].KeyMouse.Set(button.FromKey(key), action)
+	}
+	m.keymap[remapLayer].KeyMouse.Set(button.FromMouse(ebiten.MouseButtonLeft), "mouse0")
+	m.keymap[remapLayer].KeyMouse.Set(button.FromMouse(ebiten.MouseButtonMiddle), "mouse1")
+	m.keymap[remapLayer].KeyMouse.Set(button.FromMouse(ebiten.MouseButtonRight), "mouse2")
+
+	// Gamepad actions
+	for btn := ebiten.GamepadButton0; btn < ebiten.GamepadButtonMax; btn++ {
+		action := keymap.Action(fmt.Sprintf("btn%d", btn))
+		m.keymap[remapLayer].GamepadBtn.Set(btn, action)
+	}
+
+	//// Setup the axis menu's layer, which comes before the menu's since it's drawn on top.
+	m.keymap[popupLayer] = newMenuKeyMap(m.axisRoot)
+
+	colorFn := func(action keymap.Action) keymap.ButtonHandler {
+		return func(down bool) bool {
+			m.actionDown[action] = down
+			return false
+		}
+	}
+
+	axisFn := func(action keymap.Action) keymap.AxisHandler {
+		return func(val float64) bool {
+			m.axisValue[action] = val
+			return false
+		}
+	}
+
+	// UI handlers
+	uiHandlers := keymap.ButtonHandlerMap{
+		left:     colorFn(left),
+		right:    colorFn(right),
+		jump:     colorFn(jump),
+		uppercut: colorFn(uppercut),
+		slam:     colorFn(slam),
+		punch:    colorFn(punch),
+		launch:   colorFn(launch),
+	}
+	uiAxisHandlers := keymap.AxisHandlerMap{
+		move:   axisFn(move),
+		punchH: axisFn(punchH),
+		punchV: axisFn(punchV),
+	}
+	m.keymap[uiLayer] = keymap.New(uiHandlers, uiAxisHandlers)
+	// Mirror the player's bindings, which may have been loaded from the settings.
+	setDefaultKeyMap(m.keymap[uiLayer])
+	controlsOf(m.keymap[playerLayer]).apply(m.keymap[uiLayer])
+}
+
+func (m *settingsMenuState) keyRemapHandler(btn button.KeyMouse) keymap.ButtonHandler {
+	// Only remap when the button is first pressed, otherwise the key used to confirm the
+	// remap button would immediately be remapped.
+	wasDown, consumed := false, false
+	return func(down bool) bool {
+		pressed := down && !wasDown
+		wasDown = down
+		if consumed {
+			// Keep the press that was just remapped from also clicking or confirming whatever
+			// the UI has under the mouse or focused.
+			consumed = down
+			return down
+		}
+
+		_, valid := defaultKeyMap.KeyMouse.GetButton(m.remapAction)
+		if pressed && m.remap && valid {
+			m.keymap[playerLayer].KeyMouse.Set(btn, m.remapAction)
+			m.keymap[uiLayer].KeyMouse.Set(btn, m.remapAction)
+			m.remap = false
+			m.remapPrompt = ""
+			consumed = true
+			return true
+		}
+
+		// No reason to stop propagation here because either the button is up or is not
+		// remappable
+		return false
+	}
+}
+
+func (m *settingsMenuState) btnRemapHandler(btn ebiten.GamepadButton) keymap.ButtonHandler {
+	wasDown, consumed := false, false
+	return func(down bool) bool {
+		pressed := down && !wasDown
+		wasDown = down
+		if consumed {
+			consumed = down
+			return down
+		}
+		_, valid := defaultKeyMap.GamepadBtn.GetButton(m.remapAction)
+		if pressed && m.remap && valid {
+			m.keymap[playerLayer].GamepadBtn.Set(btn, m.remapAction)
+			m.keymap[uiLayer].GamepadBtn.Set(btn, m.remapAction)
+			m.remap = false
+			m.remapPrompt = ""
+			consumed = true
+			return true
+		}
+
+		// No reason to stop propagation here because either the button is up or is not
+		// remappable
+		return false
+	}
+}
+
+func (m *settingsMenuState) begin(previousState gameStateName) {
+	m.previous = previousState
+	m.closing = false
+	m.keymap[menuLayer] = m.keys
+	m.root.Show()
+	if m.axisTree == nil {
+		// Initialize here so that we have the correct number of gamepad axes.
+		if err := m.loadAxisMenu(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// capturing returns true while the menu or the axis menu is taking all keyboard input.
+func (m *settingsMenuState) capturing() bool {
+	return m.root.Capturing() || m.axisRoot.Capturing()
+}
+
+func (m *settingsMenuState) end() {
+	m.root.Hidden = true
+	m.axisRoot.Hidden = true
+	m.remap = false
+	m.remapPrompt = ""
+	m.onClose()
+}
+
+func (m *settingsMenuState) nextState() gameStateName {
+	// Wait for the menu to finish hiding so that it doesn't disappear suddenly.
+	if m.closing && m.root.Hidden {
+		return m.previous
+	}
+	return settingsMenu
+}
+
+func (m *settingsMenuState) update(dt time.Duration) {
+	m.root.Update(dt)
+	m.axisRoot.Update(dt)
+
+	m.tree.Refresh()
+	m.pageTree.Refresh()
+	if m.axisTree != nil && !m.axisRoot.Hidden {
+		m.axisTree.Refresh()
+	}
+}
+
+func (m *settingsMenuState) draw(dst *ebiten.Image, cam *camera.Camera) {
+	m.bg.Draw(dst, cam)
+	m.p.draw(dst, cam)
+
+	m.root.Draw(dst)
+	menuRect := m.root.Rect()
+	m.axisRoot.Bounds = geo.RectXYWH(menuRect.X+menuRect.W, menuRect.Y,
+		float64(m.screenWidth)-(menuRect.X+menuRect.W), menuRect.H)
+	m.axisRoot.Draw(dst)
+}
@@ -0,0 +1,210 @@
+package sprite
+
+import (
+	"fmt"
+	"time"
+)
+
+// CondOp is the comparison an AnimCond makes.
+type CondOp int
+
+const (
+	// CondTrue passes when the parameter is non-zero.
+	CondTrue CondOp = iota
+	// CondFalse passes when the parameter is zero.
+	CondFalse
+	// CondGreater passes when the parameter is greater than Value.
+	CondGreater
+	// CondLess passes when the parameter is less than Value.
+	CondLess
+)
+
+// AnimCond is a condition on one of an Animator's parameters. Parameters that have never
+// been set are 0.
+type AnimCond struct {
+	Param string
+	Op    CondOp
+	Value float64
+}
+
+func (c AnimCond) pass(val float64) bool {
+	switch c.Op {
+	case CondTrue:
+		return val != 0
+	case CondFalse:
+		return val == 0
+	case CondGreater:
+		return val > c.Value
+	case CondLess:
+		return val < c.Value
+	}
+	return false
+}
+
+// AnimTransition moves an Animator to the state named To once all of its Conds pass. If
+// OnEnd is true then the current state's animation must also have ended.
+type AnimTransition struct {
+	To    string
+	Conds []AnimCond
+	OnEnd bool
+}
+
+// AnimState is a single animation that an Animator can be in.
+type AnimState struct {
+	Name  string
+	Desc  *Desc
+	Loop  bool
+	Mode  PlayMode
+	Speed float64
+	// Priority determines which states can be interrupted by the Animator's Any
+	// transitions. They only move to a state with an equal or higher Priority than the
+	// current state, unless the current state's animation has ended.
+	Priority int
+	// Transitions are checked in order and the first whose conditions pass is taken.
+	Transitions []AnimTransition
+}
+
+// Animator chooses a Sprite's animation from a set of states based on parameters that
+// gameplay code sets, e.g.
+//  a.SetBool("moving", p.vel.X != 0)
+//  a.SetTrigger("punch")
+type Animator struct {
+	// Any transitions are checked in every state after the state's own Transitions.
+	Any      []AnimTransition
+	states   map[string]*AnimState
+	current  *AnimState
+	sprite   Sprite
+	params   map[string]float64
+	triggers map[string]bool
+}
+
+// NewAnimator creates, initializes, and returns a new Animator in the state named start.
+// It is an error for a transition to refer to a state that doesn't exist.
+func NewAnimator(states []AnimState, anyTransitions []AnimTransition, start string) (*Animator, error) {
+	a := &Animator{
+		Any:      anyTransitions,
+		states:   map[string]*AnimState{},
+		params:   map[string]float64{},
+		triggers: map[string]bool{},
+	}
+	for i := range states {
+		s := &states[i]
+		if _, ok := a.states[s.Name]; ok {
+			return nil, fmt.Errorf("duplicate anim state '%s'", s.Name)
+		}
+		if s.Desc == nil {
+			return nil, fmt.Errorf("anim state '%s': no Desc", s.Name)
+		}
+		a.states[s.Name] = s
+	}
+	for _, s := range states {
+		for _, t := range s.Transitions {
+			if _, ok := a.states[t.To]; !ok {
+				return nil, fmt.Errorf("anim state '%s': transition to unknown state '%s'", s.Name, t.To)
+			}
+		}
+	}
+	for _, t := range anyTransitions {
+		if _, ok := a.states[t.To]; !ok {
+			return nil, fmt.Errorf("any transition to unknown state '%s'", t.To)
+		}
+	}
+	if _, ok := a.states[start]; !ok {
+		return nil, fmt.Errorf("unknown start state '%s'", start)
+	}
+	a.Play(start)
+	return a, nil
+}
+
+// SetBool sets the named parameter to 1 if val is true and 0 otherwise.
+func (a *Animator) SetBool(name string, val bool) {
+	if val {
+		a.params[name] = 1
+	} else {
+		a.params[name] = 0
+	}
+}
+
+// SetFloat sets the named parameter to val.
+func (a *Animator) SetFloat(name string, val float64) {
+	a.params[name] = val
+}
+
+// SetTrigger sets the named parameter to 1 until a transition that depends on it is
+// taken, at which point it goes back to 0.
+func (a *Animator) SetTrigger(name string) {
+	a.params[name] = 1
+	a.triggers[name] = true
+}
+
+// State returns the name of the current state.
+func (a *Animator) State() string {
+	return a.current.Name
+}
+
+// Sprite returns the Sprite playing the current state's animation.
+func (a *Animator) Sprite() *Sprite {
+	return &a.sprite
+}
+
+// Play immediately moves to the named state, ignoring transitions and priorities, and
+// starts its animation from the beginning.
+func (a *Animator) Play(name string) {
+	s, ok := a.states[name]
+	if !ok {
+		return
+	}
+	a.current = s
+	a.sprite = Sprite{
+		Desc:  s.Desc,
+		Loop:  s.Loop,
+		Mode:  s.Mode,
+		Speed: s.Speed,
+	}
+	a.sprite.Start()
+}
+
+// Update updates the current animation then takes the first transition that applies.
+// It returns the events fired by the animation, see Sprite.Update.
+func (a *Animator) Update(dt time.Duration) []string {
+	events := a.sprite.Update(dt)
+
+	ended := a.sprite.Ended()
+	for _, t := range a.current.Transitions {
+		if a.try(t, ended) {
+			return events
+		}
+	}
+	for _, t := range a.Any {
+		if t.To == a.current.Name {
+			continue
+		}
+		if !ended && a.states[t.To].Priority < a.current.Priority {
+			continue
+		}
+		if a.try(t, ended) {
+			return events
+		}
+	}
+	return events
+}
+
+// try takes the transition and returns true if its conditions pass.
+func (a *Animator) try(t AnimTransition, ended bool) bool {
+	if t.OnEnd && !ended {
+		return false
+	}
+	for _, c := range t.Conds {
+		if !c.pass(a.params[c.Param]) {
+			return false
+		}
+	}
+	for _, c := range t.Conds {
+		if a.triggers[c.Param] {
+			a.params[c.Param] = 0
+			delete(a.triggers, c.Param)
+		}
+	}
+	a.Play(t.To)
+	return true
+}
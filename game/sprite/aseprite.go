@@ -0,0 +1,528 @@
+package sprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/Bredgren/geo"
+)
+
+const (
+	aseHeaderMagic = 0xA5E0
+	aseFrameMagic  = 0xF1FA
+
+	aseChunkLayer   = 0x2004
+	aseChunkCel     = 0x2005
+	aseChunkPalette = 0x2019
+	aseChunkTags    = 0x2018
+	aseChunkSlice   = 0x2022
+
+	aseLayerVisible   = 1
+	aseLayerReference = 64
+	aseLayerTypeGroup = 1
+
+	aseCelRaw        = 0
+	aseCelLinked     = 1
+	aseCelCompressed = 2
+
+	aseSliceNinePatch = 1
+	aseSliceHasPivot  = 2
+
+	// aseFlagLayerOpacity is set in the header when layer opacity values are valid.
+	aseFlagLayerOpacity = 1
+)
+
+// Aseprite takes data in Aseprite (.ase/.aseprite) format and extracts Descs from it.
+//
+// Each tag becomes a Desc with the tag's name and the frames it covers. Visible layers
+// are flattened into a single image per frame, taking layer and cel opacity into account.
+// Slices are converted with the same naming as Psd's layers, for the frames that each
+// slice key applies to.
+//  Rect <name>   The slice's bounds are added to Rects
+//  Point <name>  The slice's pivot, or its top left corner if it has none, is added to Points
+// Slices with any other name are added to Rects under their full name.
+func Aseprite(data []byte) ([]Desc, error) {
+	f, err := parseAse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.tags) == 0 {
+		return nil, fmt.Errorf("aseprite file has no tags")
+	}
+
+	// Each frame is only flattened and packed once even if multiple tags include it.
+	var a atlas
+	var packed []packedFrame
+	frames := make([]FrameDesc, len(f.frames))
+	for i, frame := range f.frames {
+		frames[i] = FrameDesc{
+			Points:   map[string][]geo.Vec{},
+			Rects:    map[string][]geo.Rect{},
+			Duration: frame.duration,
+		}
+		img, err := f.flatten(i)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %v", i, err)
+		}
+		trimmed := opaqueBounds(img)
+		if trimmed.Empty() {
+			continue
+		}
+		page, region := a.add(img, trimmed)
+		frames[i].Src = region
+		frames[i].Offset = geo.VecXYi(trimmed.Min.X, trimmed.Min.Y)
+		packed = append(packed, packedFrame{frame: &frames[i], page: page})
+	}
+	pages, err := a.images()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packed {
+		p.frame.Img = pages[p.page]
+	}
+
+	for _, s := range f.slices {
+		for k, key := range s.keys {
+			end := len(frames)
+			if k+1 < len(s.keys) {
+				end = s.keys[k+1].frame
+			}
+			for i := key.frame; i < end && i < len(frames); i++ {
+				addSlice(&frames[i], s, key)
+			}
+		}
+	}
+
+	descs := make([]Desc, 0, len(f.tags))
+	seen := map[string]bool{}
+	for _, tag := range f.tags {
+		if seen[tag.name] {
+			return nil, fmt.Errorf("duplicate sprite name '%s'", tag.name)
+		}
+		seen[tag.name] = true
+		if tag.to >= len(frames) || tag.from > tag.to {
+			return nil, fmt.Errorf("sprite '%s': frame range '%d-%d' out of bounds", tag.name, tag.from, tag.to)
+		}
+		d := Desc{
+			Name:   tag.name,
+			Size:   geo.VecXYi(f.width, f.height),
+			Frames: make([]FrameDesc, tag.to-tag.from+1),
+		}
+		copy(d.Frames, frames[tag.from:tag.to+1])
+		descs = append(descs, d)
+	}
+	return descs, nil
+}
+
+func addSlice(frame *FrameDesc, s aseSlice, key aseSliceKey) {
+	switch {
+	case pointRe.MatchString(s.name):
+		name := pointRe.FindStringSubmatch(s.name)[1]
+		p := geo.VecXY(float64(key.x), float64(key.y))
+		if key.hasPivot {
+			p.Add(geo.VecXY(float64(key.pivotX), float64(key.pivotY)))
+		}
+		frame.Points[name] = append(frame.Points[name], p)
+	default:
+		name := s.name
+		if rectRe.MatchString(s.name) {
+			name = rectRe.FindStringSubmatch(s.name)[1]
+		}
+		rect := geo.RectXYWH(float64(key.x), float64(key.y), float64(key.w), float64(key.h))
+		frame.Rects[name] = append(frame.Rects[name], rect)
+	}
+}
+
+type aseFile struct {
+	width, height int
+	colorDepth    int
+	flags         uint32
+	transparent   uint8
+	palette       color.Palette
+	layers        []aseLayer
+	frames        []aseFrame
+	tags          []aseTag
+	slices        []aseSlice
+}
+
+type aseLayer struct {
+	flags   uint16
+	kind    uint16
+	level   int
+	opacity uint8
+	name    string
+	// visible is false if the layer or any of its parent groups are hidden.
+	visible bool
+}
+
+type aseFrame struct {
+	duration time.Duration
+	cels     []aseCel
+}
+
+type aseCel struct {
+	layer   int
+	x, y    int
+	opacity uint8
+	// linkedFrame is the frame whose cel on the same layer holds the pixels, or -1 if
+	// img holds them.
+	linkedFrame int
+	img         image.Image
+}
+
+type aseTag struct {
+	from, to int
+	name     string
+}
+
+type aseSlice struct {
+	name string
+	keys []aseSliceKey
+}
+
+type aseSliceKey struct {
+	frame          int
+	x, y           int
+	w, h           int
+	hasPivot       bool
+	pivotX, pivotY int
+}
+
+// aseReader reads the little-endian primitive types used by the Aseprite format.
+type aseReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *aseReader) read(v interface{}) {
+	if r.err != nil {
+		return
+	}
+	r.err = binary.Read(r.r, binary.LittleEndian, v)
+}
+
+func (r *aseReader) byte() uint8 {
+	var v uint8
+	r.read(&v)
+	return v
+}
+
+func (r *aseReader) word() uint16 {
+	var v uint16
+	r.read(&v)
+	return v
+}
+
+func (r *aseReader) short() int16 {
+	var v int16
+	r.read(&v)
+	return v
+}
+
+func (r *aseReader) dword() uint32 {
+	var v uint32
+	r.read(&v)
+	return v
+}
+
+func (r *aseReader) long() int32 {
+	var v int32
+	r.read(&v)
+	return v
+}
+
+func (r *aseReader) skip(n int) {
+	r.read(make([]byte, n))
+}
+
+func (r *aseReader) string() string {
+	b := make([]byte, r.word())
+	r.read(b)
+	return string(b)
+}
+
+func parseAse(data []byte) (*aseFile, error) {
+	r := &aseReader{r: bytes.NewReader(data)}
+	f := &aseFile{}
+
+	r.dword() // File size
+	if magic := r.word(); r.err == nil && magic != aseHeaderMagic {
+		return nil, fmt.Errorf("not an aseprite file: invalid magic number 0x%X", magic)
+	}
+	numFrames := int(r.word())
+	f.width = int(r.word())
+	f.height = int(r.word())
+	f.colorDepth = int(r.word())
+	f.flags = r.dword()
+	r.skip(2 + 4 + 4) // Speed (deprecated) and two reserved dwords
+	f.transparent = r.byte()
+	r.skip(3 + 2 + 1 + 1 + 2 + 2 + 2 + 2 + 84) // Colors, pixel ratio, grid, and reserved
+	if r.err != nil {
+		return nil, fmt.Errorf("read header: %v", r.err)
+	}
+	switch f.colorDepth {
+	case 32, 16, 8:
+	default:
+		return nil, fmt.Errorf("unsupported color depth %d", f.colorDepth)
+	}
+
+	f.frames = make([]aseFrame, numFrames)
+	for i := range f.frames {
+		if err := f.parseFrame(r, i); err != nil {
+			return nil, fmt.Errorf("frame %d: %v", i, err)
+		}
+	}
+
+	// Resolve visibility through the group hierarchy.
+	var parents []bool
+	for i := range f.layers {
+		l := &f.layers[i]
+		if l.level < len(parents) {
+			parents = parents[:l.level]
+		}
+		l.visible = l.flags&aseLayerVisible != 0 && l.flags&aseLayerReference == 0
+		for _, p := range parents {
+			l.visible = l.visible && p
+		}
+		if l.kind == aseLayerTypeGroup {
+			parents = append(parents, l.visible)
+		}
+	}
+
+	return f, nil
+}
+
+func (f *aseFile) parseFrame(r *aseReader, frame int) error {
+	frameSize := int(r.dword())
+	if magic := r.word(); r.err == nil && magic != aseFrameMagic {
+		return fmt.Errorf("invalid magic number 0x%X", magic)
+	}
+	numChunks := int(r.word())
+	f.frames[frame].duration = time.Duration(r.word()) * time.Millisecond
+	r.skip(2)
+	if n := int(r.dword()); n != 0 {
+		numChunks = n
+	}
+	if r.err != nil {
+		return fmt.Errorf("read header: %v", r.err)
+	}
+	// The rest of the frame after its 16 byte header.
+	remaining := frameSize - 16
+
+	for i := 0; i < numChunks; i++ {
+		size := int(r.dword())
+		kind := r.word()
+		if r.err != nil {
+			return fmt.Errorf("read chunk %d: %v", i, r.err)
+		}
+		// The size includes the 6 bytes of size and type that were just read.
+		if size < 6 {
+			return fmt.Errorf("chunk %d: invalid size %d", i, size)
+		}
+		if size > remaining {
+			return fmt.Errorf("chunk %d: size %d is past the end of the frame", i, size)
+		}
+		remaining -= size
+		chunk := make([]byte, size-6)
+		r.read(chunk)
+		if r.err != nil {
+			return fmt.Errorf("read chunk %d: %v", i, r.err)
+		}
+		if err := f.parseChunk(frame, kind, chunk); err != nil {
+			return fmt.Errorf("chunk %d (type 0x%04X): %v", i, kind, err)
+		}
+	}
+	return nil
+}
+
+func (f *aseFile) parseChunk(frame int, kind uint16, data []byte) error {
+	r := &aseReader{r: bytes.NewReader(data)}
+	switch kind {
+	case aseChunkLayer:
+		var l aseLayer
+		l.flags = r.word()
+		l.kind = r.word()
+		l.level = int(r.word())
+		r.skip(2 + 2 + 2) // Default width and height, blend mode
+		l.opacity = r.byte()
+		r.skip(3)
+		l.name = r.string()
+		if f.flags&aseFlagLayerOpacity == 0 {
+			l.opacity = 0xff
+		}
+		f.layers = append(f.layers, l)
+	case aseChunkCel:
+		c := aseCel{linkedFrame: -1}
+		c.layer = int(r.word())
+		c.x = int(r.short())
+		c.y = int(r.short())
+		c.opacity = r.byte()
+		celType := r.word()
+		r.skip(2 + 5) // Z-index and reserved
+		switch celType {
+		case aseCelRaw, aseCelCompressed:
+			w, h := int(r.word()), int(r.word())
+			if r.err != nil {
+				return r.err
+			}
+			var pix io.Reader = r.r
+			if celType == aseCelCompressed {
+				zr, err := zlib.NewReader(r.r)
+				if err != nil {
+					return fmt.Errorf("decompress cel: %v", err)
+				}
+				defer zr.Close()
+				pix = zr
+			}
+			img, err := f.readPixels(pix, w, h)
+			if err != nil {
+				return fmt.Errorf("layer %d cel: %v", c.layer, err)
+			}
+			c.img = img
+		case aseCelLinked:
+			c.linkedFrame = int(r.word())
+		default:
+			// Tilemap cels are not supported, ignore them.
+			return r.err
+		}
+		f.frames[frame].cels = append(f.frames[frame].cels, c)
+	case aseChunkPalette:
+		size := int(r.dword())
+		first := int(r.dword())
+		last := int(r.dword())
+		r.skip(8)
+		if size > len(f.palette) {
+			p := make(color.Palette, size)
+			copy(p, f.palette)
+			f.palette = p
+		}
+		for i := first; i <= last && i < len(f.palette); i++ {
+			flags := r.word()
+			c := color.NRGBA{R: r.byte(), G: r.byte(), B: r.byte(), A: r.byte()}
+			if flags&1 != 0 {
+				r.string()
+			}
+			f.palette[i] = c
+		}
+	case aseChunkTags:
+		n := int(r.word())
+		r.skip(8)
+		for i := 0; i < n; i++ {
+			var t aseTag
+			t.from = int(r.word())
+			t.to = int(r.word())
+			r.skip(1 + 2 + 6 + 3 + 1) // Direction, repeat, reserved, color
+			t.name = r.string()
+			f.tags = append(f.tags, t)
+		}
+	case aseChunkSlice:
+		var s aseSlice
+		n := int(r.dword())
+		flags := r.dword()
+		r.dword()
+		s.name = r.string()
+		for i := 0; i < n; i++ {
+			var k aseSliceKey
+			k.frame = int(r.dword())
+			k.x = int(r.long())
+			k.y = int(r.long())
+			k.w = int(r.dword())
+			k.h = int(r.dword())
+			if flags&aseSliceNinePatch != 0 {
+				r.skip(4 * 4)
+			}
+			if flags&aseSliceHasPivot != 0 {
+				k.hasPivot = true
+				k.pivotX = int(r.long())
+				k.pivotY = int(r.long())
+			}
+			s.keys = append(s.keys, k)
+		}
+		f.slices = append(f.slices, s)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	return nil
+}
+
+// readPixels reads a w x h image in the file's color depth.
+func (f *aseFile) readPixels(r io.Reader, w, h int) (image.Image, error) {
+	pix, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read pixels: %v", err)
+	}
+	bytesPerPixel := f.colorDepth / 8
+	if len(pix) < w*h*bytesPerPixel {
+		return nil, fmt.Errorf("expected %d bytes of pixel data but got %d", w*h*bytesPerPixel, len(pix))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		var c color.NRGBA
+		switch f.colorDepth {
+		case 32:
+			c = color.NRGBA{R: pix[4*i], G: pix[4*i+1], B: pix[4*i+2], A: pix[4*i+3]}
+		case 16:
+			v := pix[2*i]
+			c = color.NRGBA{R: v, G: v, B: v, A: pix[2*i+1]}
+		case 8:
+			index := pix[i]
+			if index == f.transparent {
+				break
+			}
+			if int(index) >= len(f.palette) || f.palette[index] == nil {
+				return nil, fmt.Errorf("color index %d is not in the palette", index)
+			}
+			c = color.NRGBAModel.Convert(f.palette[index]).(color.NRGBA)
+		}
+		img.SetNRGBA(i%w, i/w, c)
+	}
+	return img, nil
+}
+
+// flatten draws all visible layers of the given frame into a single image. Blend modes
+// other than normal are drawn as normal.
+func (f *aseFile) flatten(frame int) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, f.width, f.height))
+	cels := map[int]aseCel{}
+	for _, c := range f.frames[frame].cels {
+		cels[c.layer] = c
+	}
+	for layer, l := range f.layers {
+		c, ok := cels[layer]
+		if !ok || !l.visible {
+			continue
+		}
+		pixels := c
+		if c.linkedFrame >= 0 {
+			if c.linkedFrame >= len(f.frames) {
+				return nil, fmt.Errorf("layer '%s': linked frame %d out of bounds", l.name, c.linkedFrame)
+			}
+			found := false
+			for _, linked := range f.frames[c.linkedFrame].cels {
+				if linked.layer == layer && linked.img != nil {
+					pixels, found = linked, true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("layer '%s': linked frame %d has no cel", l.name, c.linkedFrame)
+			}
+		}
+		opacity := uint8(uint16(l.opacity) * uint16(c.opacity) / 0xff)
+		mask := image.NewUniform(color.Alpha{A: opacity})
+		b := pixels.img.Bounds()
+		dstRect := b.Add(image.Pt(pixels.x, pixels.y))
+		draw.DrawMask(img, dstRect, pixels.img, b.Min, mask, image.Point{}, draw.Over)
+	}
+	return img, nil
+}
@@ -0,0 +1,129 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	// atlasSize is the width and height of each atlas page. Frames larger than this get
+	// a page of their own.
+	atlasSize = 1024
+	// atlasPadding is the number of transparent pixels left between frames so that
+	// filtering doesn't bleed neighboring frames into each other.
+	atlasPadding = 1
+)
+
+// atlasPage is a single texture that frames are packed into using rows of shelves.
+type atlasPage struct {
+	img    *image.RGBA
+	x      int // Left edge of the next free space on the current shelf
+	shelfY int // Top of the current shelf
+	shelfH int // Height of the tallest frame on the current shelf
+}
+
+// fit returns the position that a w x h region would be placed at and whether or not it
+// fits on the page.
+func (p *atlasPage) fit(w, h int) (pos image.Point, ok bool) {
+	size := p.img.Bounds().Size()
+	if p.x+w <= size.X && p.shelfY+h <= size.Y {
+		return image.Pt(p.x, p.shelfY), true
+	}
+	// Start a new shelf
+	y := p.shelfY + p.shelfH + atlasPadding
+	if w <= size.X && y+h <= size.Y {
+		return image.Pt(0, y), true
+	}
+	return image.Point{}, false
+}
+
+func (p *atlasPage) place(pos image.Point, w, h int) {
+	if pos.Y != p.shelfY {
+		p.shelfY = pos.Y
+		p.shelfH = 0
+	}
+	p.x = pos.X + w + atlasPadding
+	if h > p.shelfH {
+		p.shelfH = h
+	}
+}
+
+// atlas packs many small images into a few large ones.
+type atlas struct {
+	pages []*atlasPage
+}
+
+// add copies the src region of img into the atlas and returns the index of the page
+// and the region of that page that it was copied to.
+func (a *atlas) add(img image.Image, src image.Rectangle) (page int, region image.Rectangle) {
+	w, h := src.Dx(), src.Dy()
+	for i, p := range a.pages {
+		if pos, ok := p.fit(w, h); ok {
+			return i, a.copyTo(p, i, pos, img, src)
+		}
+	}
+
+	size := image.Pt(atlasSize, atlasSize)
+	if w > size.X {
+		size.X = w
+	}
+	if h > size.Y {
+		size.Y = h
+	}
+	p := &atlasPage{img: image.NewRGBA(image.Rectangle{Max: size})}
+	a.pages = append(a.pages, p)
+	return len(a.pages) - 1, a.copyTo(p, len(a.pages)-1, image.Point{}, img, src)
+}
+
+func (a *atlas) copyTo(p *atlasPage, page int, pos image.Point, img image.Image, src image.Rectangle) image.Rectangle {
+	region := image.Rectangle{Min: pos, Max: pos.Add(src.Size())}
+	draw.Draw(p.img, region, img, src.Min, draw.Src)
+	p.place(pos, src.Dx(), src.Dy())
+	return region
+}
+
+// images converts each page into an ebiten.Image.
+func (a *atlas) images() ([]*ebiten.Image, error) {
+	imgs := make([]*ebiten.Image, len(a.pages))
+	for i, p := range a.pages {
+		img, err := ebiten.NewImageFromImage(p.img, ebiten.FilterNearest)
+		if err != nil {
+			return nil, fmt.Errorf("create ebiten image for atlas page %d: %v", i, err)
+		}
+		imgs[i] = img
+	}
+	return imgs, nil
+}
+
+// opaqueBounds returns the smallest rectangle containing all pixels of img that are not
+// fully transparent. The result is empty if all of them are.
+func opaqueBounds(img *image.RGBA) image.Rectangle {
+	b := img.Bounds()
+	bounds := image.Rectangle{Min: b.Max, Max: b.Min}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] == 0 {
+				continue
+			}
+			if x < bounds.Min.X {
+				bounds.Min.X = x
+			}
+			if y < bounds.Min.Y {
+				bounds.Min.Y = y
+			}
+			if x+1 > bounds.Max.X {
+				bounds.Max.X = x + 1
+			}
+			if y+1 > bounds.Max.Y {
+				bounds.Max.Y = y + 1
+			}
+		}
+	}
+	if bounds.Empty() {
+		return image.Rectangle{}
+	}
+	return bounds
+}
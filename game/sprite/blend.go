@@ -0,0 +1,64 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// blendFn combines a backdrop color channel cb with a source color channel cs. Both are
+// non-premultiplied and between 0 and 1.
+type blendFn func(cb, cs float64) float64
+
+// blendFns maps PSD blend mode keys to their functions.
+var blendFns = map[string]blendFn{
+	"norm": func(cb, cs float64) float64 { return cs },
+	"mul ": func(cb, cs float64) float64 { return cb * cs },
+	"scrn": func(cb, cs float64) float64 { return cb + cs - cb*cs },
+	"dark": math.Min,
+	"lite": math.Max,
+	"lddg": func(cb, cs float64) float64 { return math.Min(1, cb+cs) },
+	"over": func(cb, cs float64) float64 {
+		if cb <= 0.5 {
+			return 2 * cb * cs
+		}
+		return 1 - 2*(1-cb)*(1-cs)
+	},
+}
+
+// blend composites src over dst using the given PSD blend mode. The opacity of src is
+// multiplied by opacity and, if mask is not nil, the alpha of mask. All images must have
+// the same bounds.
+func blend(dst, src, mask *image.RGBA, opacity float64, mode string) error {
+	if mode == "" {
+		mode = "norm"
+	}
+	fn, ok := blendFns[mode]
+	if !ok {
+		return fmt.Errorf("unsupported blend mode '%s'", mode)
+	}
+
+	for i := 0; i < len(dst.Pix); i += 4 {
+		sa := float64(src.Pix[i+3]) / 0xff * opacity
+		if mask != nil {
+			sa *= float64(mask.Pix[i+3]) / 0xff
+		}
+		if sa == 0 {
+			continue
+		}
+		da := float64(dst.Pix[i+3]) / 0xff
+		srcA := float64(src.Pix[i+3])
+		for c := 0; c < 3; c++ {
+			// Pix holds premultiplied values
+			cs := float64(src.Pix[i+c]) / srcA
+			cb := 0.0
+			if da > 0 {
+				cb = float64(dst.Pix[i+c]) / 0xff / da
+			}
+			co := cs*sa*(1-da) + fn(cb, cs)*sa*da + cb*da*(1-sa)
+			dst.Pix[i+c] = uint8(math.Min(1, co)*0xff + 0.5)
+		}
+		dst.Pix[i+3] = uint8((sa+da*(1-sa))*0xff + 0.5)
+	}
+	return nil
+}
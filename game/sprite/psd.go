@@ -2,12 +2,14 @@ package sprite
 
 import (
 	"fmt"
+	"image"
+	"image/draw"
 	"regexp"
 	"strconv"
 	"time"
 
+	"github.com/Bredgren/game1/game/util"
 	"github.com/Bredgren/geo"
-	"github.com/hajimehoshi/ebiten"
 	"github.com/solovev/gopsd"
 )
 
@@ -16,9 +18,13 @@ var (
 	frameRe  = regexp.MustCompile(`Frame +(\d+) +([\d.\w]+)`)
 	rectRe   = regexp.MustCompile(`Rect +(\w+)`)
 	pointRe  = regexp.MustCompile(`Point +(\w+)`)
+	eventRe  = regexp.MustCompile(`Event +(\w+)`)
 	imgRe    = regexp.MustCompile(`Img`)
 )
 
+// psdFlagHidden is set in a layer's flags when its visibility is turned off.
+const psdFlagHidden = 0x02
+
 // Psd takes data in psd format and extracts Descs from it.
 //
 // Layers are parsed with the following format.
@@ -26,14 +32,31 @@ var (
 //    Frame <#> <duration>
 //      Rect <name>
 //      Point <name>
+//      Event <name>
 //      Img
 //        <If Img is a folder then the layers it contains are joined, their names don't matter>
+//
+// Every layer in a Sprite must be a Frame, numbered from 0 without gaps, and every Frame
+// must have exactly one Img. Problems with the layer structure don't stop parsing, they
+// are all collected and returned together as a util.ErrorList.
+//
+// Img, and the layers inside of it if it's a folder, are composited using their visibility,
+// opacity, blend mode and clipping masks. Visibility of Sprite and Frame layers is ignored
+// since frames are usually hidden while working on other ones.
+//
+// Each frame's image is trimmed to its opaque bounds and packed into atlas images that are
+// shared between all of the returned Descs.
 func Psd(data []byte) ([]Desc, error) {
 	doc, err := gopsd.ParseFromBuffer(data)
 	if err != nil {
 		return nil, err
 	}
+	docBounds := image.Rect(0, 0, int(doc.Width), int(doc.Height))
 	var descs []Desc
+	var errs util.ErrorList
+	var a atlas
+	var packed []packedFrame
+	spriteNames := map[string]bool{}
 	rootLayer := doc.GetTreeRepresentation()
 	for _, layer := range rootLayer.Children {
 		m := spriteRe.FindStringSubmatch(layer.Name)
@@ -41,10 +64,17 @@ func Psd(data []byte) ([]Desc, error) {
 			continue
 		}
 		spriteName := m[1]
+		if spriteNames[spriteName] {
+			errs = append(errs, fmt.Errorf("duplicate sprite name '%s'", spriteName))
+			continue
+		}
+		spriteNames[spriteName] = true
 		d := Desc{
 			Name:   spriteName,
-			Frames: make([]FrameDesc, len(layer.Children)),
+			Size:   geo.VecXYi(docBounds.Dx(), docBounds.Dy()),
+			Frames: make([]FrameDesc, countFrames(layer)),
 		}
+		seen := make([]bool, len(d.Frames))
 		for i := range d.Frames {
 			d.Frames[i].Points = map[string][]geo.Vec{}
 			d.Frames[i].Rects = map[string][]geo.Rect{}
@@ -52,22 +82,28 @@ func Psd(data []byte) ([]Desc, error) {
 		for _, spriteLayer := range layer.Children {
 			m := frameRe.FindStringSubmatch(spriteLayer.Name)
 			if len(m) == 0 {
+				errs = append(errs, fmt.Errorf("sprite '%s': layer '%s' is not a frame", spriteName, spriteLayer.Name))
 				continue
 			}
 			frameNum, err := strconv.Atoi(m[1])
 			if err != nil {
-				return nil, fmt.Errorf("sprite '%s': invalid frame number '%s': %v", spriteName, m[1], err)
+				errs = append(errs, fmt.Errorf("sprite '%s': invalid frame number '%s': %v", spriteName, m[1], err))
+				continue
 			}
 			if frameNum >= len(d.Frames) {
-				return nil, fmt.Errorf("sprite '%s': frame number '%d' out of bounds", spriteName, frameNum)
+				errs = append(errs, fmt.Errorf("sprite '%s': frame number '%d' out of bounds", spriteName, frameNum))
+				continue
 			}
-			if d.Frames[frameNum].Img != nil {
-				return nil, fmt.Errorf("sprite '%s': duplicate frame number '%d'", spriteName, frameNum)
+			if seen[frameNum] {
+				errs = append(errs, fmt.Errorf("sprite '%s': duplicate frame number '%d'", spriteName, frameNum))
+				continue
 			}
+			seen[frameNum] = true
 			d.Frames[frameNum].Duration, err = time.ParseDuration(m[2])
 			if err != nil {
-				return nil, fmt.Errorf("sprite %s, frame %d: %v", spriteName, frameNum, err)
+				errs = append(errs, fmt.Errorf("sprite %s, frame %d: %v", spriteName, frameNum, err))
 			}
+			numImg := 0
 			for _, frameLayer := range spriteLayer.Children {
 				r := frameLayer.Rectangle
 				switch {
@@ -81,41 +117,135 @@ func Psd(data []byte) ([]Desc, error) {
 					name := m[1]
 					p := geo.VecXY(float64(r.X), float64(r.Y))
 					d.Frames[frameNum].Points[name] = append(d.Frames[frameNum].Points[name], p)
+				case eventRe.MatchString(frameLayer.Name):
+					m := eventRe.FindStringSubmatch(frameLayer.Name)
+					d.Frames[frameNum].Events = append(d.Frames[frameNum].Events, m[1])
 				case imgRe.MatchString(frameLayer.Name):
-					img, _ := ebiten.NewImage(int(doc.Width), int(doc.Height), ebiten.FilterNearest)
-					if frameLayer.IsFolder {
-						// Backwards to make sure that layers are drawn in the correct order
-						for i := len(frameLayer.Children) - 1; i >= 0; i-- {
-							child := frameLayer.Children[i]
-							if err := drawImg(img, child); err != nil {
-								return nil, fmt.Errorf("draw img layer for sprite '%s', frame '%d': %v", spriteName, frameNum, err)
-							}
-						}
-					} else {
-						if err := drawImg(img, frameLayer); err != nil {
-							return nil, fmt.Errorf("draw img for sprite '%s', frame '%d': %v", spriteName, frameNum, err)
-						}
+					numImg++
+					if numImg > 1 {
+						errs = append(errs, fmt.Errorf("sprite '%s', frame '%d': duplicate img", spriteName, frameNum))
+						continue
+					}
+					img, err := frameImage(docBounds, frameLayer)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("draw img for sprite '%s', frame '%d': %v", spriteName, frameNum, err))
+						continue
+					}
+					trimmed := opaqueBounds(img)
+					if trimmed.Empty() {
+						continue
 					}
-					d.Frames[frameNum].Img = img
+					page, region := a.add(img, trimmed)
+					d.Frames[frameNum].Src = region
+					d.Frames[frameNum].Offset = geo.VecXYi(trimmed.Min.X, trimmed.Min.Y)
+					packed = append(packed, packedFrame{frame: &d.Frames[frameNum], page: page})
+				default:
+					errs = append(errs, fmt.Errorf("sprite '%s', frame '%d': unrecognized layer '%s'", spriteName, frameNum, frameLayer.Name))
 				}
 			}
+			if numImg == 0 {
+				errs = append(errs, fmt.Errorf("sprite '%s', frame '%d': no img", spriteName, frameNum))
+			}
+		}
+		for i, ok := range seen {
+			if !ok {
+				errs = append(errs, fmt.Errorf("sprite '%s': missing frame number '%d'", spriteName, i))
+			}
 		}
 		descs = append(descs, d)
 	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	pages, err := a.images()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packed {
+		p.frame.Img = pages[p.page]
+	}
+
 	return descs, nil
 }
 
-func drawImg(img *ebiten.Image, layer *gopsd.Layer) error {
-	rawImg, err := layer.GetImage()
-	if err != nil {
-		return fmt.Errorf("get image from layer: %v", err)
+// countFrames returns the number of children of a Sprite layer that are named as frames.
+func countFrames(spriteLayer *gopsd.Layer) int {
+	n := 0
+	for _, l := range spriteLayer.Children {
+		if frameRe.MatchString(l.Name) {
+			n++
+		}
+	}
+	return n
+}
+
+// packedFrame remembers which atlas page a frame was packed into until the pages are
+// converted to ebiten images.
+type packedFrame struct {
+	frame *FrameDesc
+	page  int
+}
+
+// frameImage draws a frame's Img layer into an image the size of bounds. If Img is a folder
+// then the layers in it are joined. The image is empty if Img is hidden.
+func frameImage(bounds image.Rectangle, imgLayer *gopsd.Layer) (*image.RGBA, error) {
+	if imgLayer.Flags&psdFlagHidden != 0 {
+		return image.NewRGBA(bounds), nil
+	}
+	layers := []*gopsd.Layer{imgLayer}
+	if imgLayer.IsFolder {
+		layers = imgLayer.Children
+	}
+	return compositeLayers(bounds, layers)
+}
+
+// compositeLayers draws layers, which are ordered from top to bottom, into a single image
+// the size of bounds. Hidden layers are skipped.
+func compositeLayers(bounds image.Rectangle, layers []*gopsd.Layer) (*image.RGBA, error) {
+	img := image.NewRGBA(bounds)
+	// clipBase is the nearest unclipped layer below the current one. Clipped layers only
+	// show where it is opaque.
+	var clipBase *image.RGBA
+	clipBaseHidden := false
+	// Backwards to make sure that layers are drawn in the correct order
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		if layer.IsFolder {
+			return nil, fmt.Errorf("layer '%s': nested folders are not supported", layer.Name)
+		}
+		layerImg, err := layerImage(bounds, layer)
+		if err != nil {
+			return nil, fmt.Errorf("layer '%s': %v", layer.Name, err)
+		}
+		hidden := layer.Flags&psdFlagHidden != 0
+		if !layer.Clipping {
+			clipBase, clipBaseHidden = layerImg, hidden
+		}
+		if hidden || (layer.Clipping && clipBaseHidden) {
+			continue
+		}
+		var mask *image.RGBA
+		if layer.Clipping {
+			mask = clipBase
+		}
+		if err := blend(img, layerImg, mask, float64(layer.Opacity)/0xff, layer.BlendMode); err != nil {
+			return nil, fmt.Errorf("layer '%s': %v", layer.Name, err)
+		}
 	}
-	i, err := ebiten.NewImageFromImage(rawImg, ebiten.FilterNearest)
+	return img, nil
+}
+
+// layerImage returns the layer's pixels placed within an image the size of bounds.
+func layerImage(bounds image.Rectangle, layer *gopsd.Layer) (*image.RGBA, error) {
+	img := image.NewRGBA(bounds)
+	rawImg, err := layer.GetImage()
 	if err != nil {
-		return fmt.Errorf("create ebiten image from layer: %v", err)
+		return nil, fmt.Errorf("get image from layer: %v", err)
 	}
-	opts := ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(float64(layer.Rectangle.X), float64(layer.Rectangle.Y))
-	img.DrawImage(i, &opts)
-	return nil
+	r := rawImg.Bounds()
+	dstRect := r.Sub(r.Min).Add(image.Pt(int(layer.Rectangle.X), int(layer.Rectangle.Y)))
+	draw.Draw(img, dstRect, rawImg, r.Min, draw.Src)
+	return img, nil
 }
@@ -0,0 +1,117 @@
+package sprite
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Bredgren/game1/game/util"
+	"github.com/solovev/gopsd"
+)
+
+// The files in testdata are made by testdata/gen.go.
+
+func readTestPsd(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestPsdErrors(t *testing.T) {
+	_, err := Psd(readTestPsd(t, "errors.psd"))
+	errs, ok := err.(util.ErrorList)
+	if !ok {
+		t.Fatalf("err = %v, want a util.ErrorList", err)
+	}
+	want := []string{
+		"sprite 'missing': frame number '2' out of bounds",
+		"sprite 'missing': missing frame number '1'",
+		"sprite 'duplicate': duplicate frame number '0'",
+		"sprite 'duplicate': missing frame number '1'",
+		"sprite 'noimg', frame '0': no img",
+		"sprite 'twoimg', frame '0': duplicate img",
+	}
+	got := map[string]bool{}
+	for _, e := range errs {
+		got[e.Error()] = true
+	}
+	for _, msg := range want {
+		if !got[msg] {
+			t.Errorf("missing error %q", msg)
+		}
+	}
+	if len(errs) != len(want) {
+		t.Errorf("got %d errors, want %d:\n%v", len(errs), len(want), errs)
+	}
+}
+
+func TestFrameImage(t *testing.T) {
+	doc, err := gopsd.ParseFromBuffer(readTestPsd(t, "composite.psd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds := image.Rect(0, 0, int(doc.Width), int(doc.Height))
+
+	imgLayers := map[int]*gopsd.Layer{}
+	for _, frame := range doc.GetTreeRepresentation().Children[0].Children {
+		m := frameRe.FindStringSubmatch(frame.Name)
+		n, _ := strconv.Atoi(m[1])
+		for _, l := range frame.Children {
+			if imgRe.MatchString(l.Name) {
+				imgLayers[n] = l
+			}
+		}
+	}
+
+	var (
+		none = color.RGBA{}
+		red  = color.RGBA{0xff, 0, 0, 0xff}
+		blue = color.RGBA{0, 0, 0xff, 0xff}
+		// Black at half opacity over white
+		gray = color.RGBA{0x7f, 0x7f, 0x7f, 0xff}
+	)
+	cases := []struct {
+		frame       int
+		name        string
+		left, right color.RGBA
+	}{
+		{0, "hidden layer in folder", red, red},
+		{1, "opacity", gray, gray},
+		{2, "clipped", blue, none},
+		{3, "hidden img layer", none, none},
+		{4, "img layer", red, red},
+		{5, "hidden img folder", none, none},
+	}
+	for _, c := range cases {
+		l, ok := imgLayers[c.frame]
+		if !ok {
+			t.Errorf("%s: no img in frame %d", c.name, c.frame)
+			continue
+		}
+		img, err := frameImage(bounds, l)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if got := img.RGBAAt(0, 0); !nearColor(got, c.left) {
+			t.Errorf("%s: left pixel = %v, want %v", c.name, got, c.left)
+		}
+		if got := img.RGBAAt(1, 0); !nearColor(got, c.right) {
+			t.Errorf("%s: right pixel = %v, want %v", c.name, got, c.right)
+		}
+	}
+}
+
+// nearColor reports whether a and b are the same except for rounding.
+func nearColor(a, b color.RGBA) bool {
+	near := func(x, y uint8) bool {
+		d := int(x) - int(y)
+		return -1 <= d && d <= 1
+	}
+	return near(a.R, b.R) && near(a.G, b.G) && near(a.B, b.B) && near(a.A, b.A)
+}
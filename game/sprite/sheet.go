@@ -0,0 +1,135 @@
+package sprite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"time"
+
+	// For decoding sprite sheets
+	_ "image/png"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// SheetDesc is the JSON description of a sprite sheet. Frames lists every frame in the
+// sheet and Tags groups ranges of them into named sprites.
+//
+// Example:
+//  {
+//    "frames": [
+//      {
+//        "rect": {"x": 0, "y": 0, "w": 16, "h": 32},
+//        "duration": "100ms",
+//        "points": {"anchor": [{"x": 8, "y": 32}]},
+//        "rects": {"hit": [{"x": 2, "y": 0, "w": 12, "h": 32}]},
+//        "events": ["step"]
+//      }
+//    ],
+//    "tags": [
+//      {"name": "idle", "from": 0, "to": 0}
+//    ]
+//  }
+type SheetDesc struct {
+	Frames []SheetFrameDesc `json:"frames"`
+	Tags   []SheetTagDesc   `json:"tags"`
+}
+
+// SheetFrameDesc describes a single frame of a sprite sheet. Points and Rects are
+// relative to the top left corner of Rect.
+type SheetFrameDesc struct {
+	Rect     geo.Rect              `json:"rect"`
+	Duration string                `json:"duration"`
+	Points   map[string][]geo.Vec  `json:"points"`
+	Rects    map[string][]geo.Rect `json:"rects"`
+	Events   []string              `json:"events"`
+}
+
+// SheetTagDesc names the frames From through To, inclusive, as a sprite.
+type SheetTagDesc struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// Sheet takes a sprite sheet in png format and its JSON description (see SheetDesc) and
+// extracts Descs from them. There is one Desc for each tag.
+func Sheet(pngData, descData []byte) ([]Desc, error) {
+	var sheetDesc SheetDesc
+	if err := json.Unmarshal(descData, &sheetDesc); err != nil {
+		return nil, fmt.Errorf("parse sheet description: %v", err)
+	}
+
+	rawImg, _, err := image.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return nil, fmt.Errorf("decode sheet image: %v", err)
+	}
+	img, err := ebiten.NewImageFromImage(rawImg, ebiten.FilterNearest)
+	if err != nil {
+		return nil, fmt.Errorf("create ebiten image from sheet: %v", err)
+	}
+
+	return sheetDescs(img, rawImg.Bounds(), sheetDesc)
+}
+
+func sheetDescs(img *ebiten.Image, imgBounds image.Rectangle, sheetDesc SheetDesc) ([]Desc, error) {
+	if len(sheetDesc.Tags) == 0 {
+		return nil, fmt.Errorf("sheet has no tags")
+	}
+
+	frames := make([]FrameDesc, len(sheetDesc.Frames))
+	for i, f := range sheetDesc.Frames {
+		src := image.Rect(int(f.Rect.X), int(f.Rect.Y), int(f.Rect.X+f.Rect.W), int(f.Rect.Y+f.Rect.H))
+		if !src.In(imgBounds) {
+			return nil, fmt.Errorf("frame %d: rect %v is outside of the sheet %v", i, src, imgBounds)
+		}
+		duration, err := time.ParseDuration(f.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %v", i, err)
+		}
+		frames[i] = FrameDesc{
+			Img:      img,
+			Src:      src,
+			Points:   f.Points,
+			Rects:    f.Rects,
+			Events:   f.Events,
+			Duration: duration,
+		}
+		if frames[i].Points == nil {
+			frames[i].Points = map[string][]geo.Vec{}
+		}
+		if frames[i].Rects == nil {
+			frames[i].Rects = map[string][]geo.Rect{}
+		}
+	}
+
+	descs := make([]Desc, 0, len(sheetDesc.Tags))
+	seen := map[string]bool{}
+	for _, tag := range sheetDesc.Tags {
+		if seen[tag.Name] {
+			return nil, fmt.Errorf("duplicate sprite name '%s'", tag.Name)
+		}
+		seen[tag.Name] = true
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("sprite '%s': frame range '%d-%d' out of bounds", tag.Name, tag.From, tag.To)
+		}
+		d := Desc{
+			Name:   tag.Name,
+			Frames: make([]FrameDesc, tag.To-tag.From+1),
+		}
+		copy(d.Frames, frames[tag.From:tag.To+1])
+		for _, f := range d.Frames {
+			w, h := float64(f.Src.Dx()), float64(f.Src.Dy())
+			if w > d.Size.X {
+				d.Size.X = w
+			}
+			if h > d.Size.Y {
+				d.Size.Y = h
+			}
+		}
+		descs = append(descs, d)
+	}
+	return descs, nil
+}
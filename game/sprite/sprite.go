@@ -1,6 +1,7 @@
 package sprite
 
 import (
+	"image"
 	"time"
 
 	"github.com/Bredgren/geo"
@@ -9,36 +10,83 @@ import (
 
 // FrameDesc describes a single frame of a sprite
 type FrameDesc struct {
+	// Img is the atlas image that holds this frame. Only the Src region belongs to the
+	// frame. Img is nil if the frame has no visible pixels.
 	Img *ebiten.Image
+	// Src is the region of Img that contains this frame.
+	Src image.Rectangle
+	// Offset is the position of Src's top left corner relative to the sprite's top left
+	// corner. It accounts for the transparent space trimmed from the frame.
+	Offset geo.Vec
 	// Points maps a name to a list of positions relative to the sprite's top left corner
 	Points map[string][]geo.Vec
 	// Rects maps a name to a list of rectangles relative to the sprite's top left corner
-	Rects    map[string][]geo.Rect
+	Rects map[string][]geo.Rect
+	// Events are the names of gameplay events that happen when the frame is entered, e.g.
+	// the moment an attack's hitbox becomes active.
+	Events   []string
 	Duration time.Duration
 }
 
 // Desc holds information that is shared between all instances of the same sprite.
 type Desc struct {
-	Name   string
+	Name string
+	// Size is the untrimmed size of the sprite's frames.
+	Size   geo.Vec
 	Frames []FrameDesc
 }
 
+// PlayMode determines the order in which a Sprite's frames are played.
+type PlayMode int
+
+const (
+	// PlayForward plays frames from first to last.
+	PlayForward PlayMode = iota
+	// PlayReverse plays frames from last to first.
+	PlayReverse
+	// PlayPingPong plays frames from first to last then back to first again.
+	PlayPingPong
+)
+
 // Sprite is an instance of a sprite described by Desc. This allows one to create many
 // copies of the same sprite, each animating independently, but all of the common data
 // is shared.
 type Sprite struct {
 	*Desc
-	Loop           bool
+	Loop bool
+	Mode PlayMode
+	// Speed scales the dt given to Update. A Speed of 0 is treated as 1.
+	Speed float64
+	// OnFrame is optional. It is called with the frame number each time Start or Update
+	// enters a frame.
+	OnFrame        func(frame int)
 	frame          int
+	dir            int // 1 when moving toward the last frame, -1 when moving toward the first
 	untilNextFrame time.Duration
+	events         []string
+	loopRange      bool
+	loopFrom       int
+	loopTo         int
 }
 
-// Img returns the image for the current frame.
+// Img returns the atlas image for the current frame. Only the region returned by Src
+// belongs to the frame.
 func (s *Sprite) Img() *ebiten.Image {
 	return s.Frames[s.frame].Img
 }
 
-// Points returns the points associated with the given name for the current frame.
+// Src returns the region of Img that contains the current frame.
+func (s *Sprite) Src() image.Rectangle {
+	return s.Frames[s.frame].Src
+}
+
+// Frame returns the current frame number.
+func (s *Sprite) Frame() int {
+	return s.frame
+}
+
+// Points returns the points associated with the given name for the current frame. If
+// the current frame has none then the closest previous frame that does is used.
 func (s *Sprite) Points(name string) []geo.Vec {
 	frame := s.frame
 	points, ok := s.Frames[frame].Points[name]
@@ -49,45 +97,224 @@ func (s *Sprite) Points(name string) []geo.Vec {
 	return points
 }
 
-// Rects returns the rectangles associated with the given name for the current frame.
+// Rects returns the rectangles associated with the given name for the current frame. If
+// the current frame has none then the closest previous frame that does is used.
 func (s *Sprite) Rects(name string) []geo.Rect {
-	return s.Frames[s.frame].Rects[name]
+	frame := s.frame
+	rects, ok := s.Frames[frame].Rects[name]
+	for !ok && frame > 0 {
+		frame--
+		rects, ok = s.Frames[frame].Rects[name]
+	}
+	return rects
 }
 
-// Start the sprite from the first frame.
+// Start the sprite from the first frame, or the last frame if Mode is PlayReverse.
 func (s *Sprite) Start() {
-	s.frame = 0
+	if len(s.Frames) == 0 {
+		return
+	}
+	s.frame, s.dir = 0, 1
+	if s.Mode == PlayReverse {
+		s.frame, s.dir = len(s.Frames)-1, -1
+	}
+	s.untilNextFrame = s.Frames[s.frame].Duration
+	s.enter()
+}
+
+// SetLoopRange makes a looping sprite repeat only frames from through to, inclusive,
+// once it reaches them. Frames outside of the range are played once on the way in. This
+// is useful for animations with a lead-in, e.g. frames 0-2 wind up and 3-7 repeat.
+func (s *Sprite) SetLoopRange(from, to int) {
+	last := len(s.Frames) - 1
+	s.loopFrom = clamp(from, 0, last)
+	s.loopTo = clamp(to, s.loopFrom, last)
+	s.loopRange = true
+}
+
+// ClearLoopRange makes a looping sprite repeat all of its frames.
+func (s *Sprite) ClearLoopRange() {
+	s.loopRange = false
+}
+
+// SetFrame jumps to the given frame and restarts its duration. OnFrame is not called.
+func (s *Sprite) SetFrame(frame int) {
+	if len(s.Frames) == 0 {
+		return
+	}
+	s.frame = clamp(frame, 0, len(s.Frames)-1)
+	if s.dir == 0 {
+		s.dir = s.startDir()
+	}
 	s.untilNextFrame = s.Frames[s.frame].Duration
 }
 
+// Seek jumps to the frame that would be showing t time after Start, following Mode and
+// Loop. Speed is ignored and OnFrame is not called for the frames skipped over.
+func (s *Sprite) Seek(t time.Duration) {
+	onFrame := s.OnFrame
+	s.OnFrame = nil
+	s.Start()
+	s.advance(t)
+	s.OnFrame = onFrame
+	s.events = s.events[:0]
+}
+
 // Ended returns true when the sprite is not looping and is done with the last frame.
 func (s *Sprite) Ended() bool {
-	return !s.Loop && s.frame >= len(s.Frames)-1 && s.untilNextFrame <= 0
+	if s.Loop || len(s.Frames) == 0 || s.untilNextFrame > 0 {
+		return false
+	}
+	switch s.Mode {
+	case PlayReverse:
+		return s.frame == 0
+	case PlayPingPong:
+		return s.frame == 0 && (s.dir < 0 || len(s.Frames) == 1)
+	}
+	return s.frame == len(s.Frames)-1
 }
 
-// Update subtracts dt from the time remaining on the current frame then advances the
-// frame when it reaches 0. If Loop is true then the animation will start back from
-// the beginning after reaching the end.
-func (s *Sprite) Update(dt time.Duration) {
-	s.untilNextFrame -= dt
-	if s.Ended() {
+// Update subtracts dt, scaled by Speed, from the time remaining on the current frame and
+// advances through as many frames as that time covers. If Loop is true then the
+// animation will start back from the beginning (or the loop range) after reaching the
+// end. Whole loops that dt covers are skipped over rather than played, so their frames
+// aren't entered again.
+//
+// The returned slice holds, in order, the Events of every frame entered since the last
+// call to Update, including the first frame if Start was called. It's nil if there were
+// none, otherwise it's a new slice that the caller can keep.
+func (s *Sprite) Update(dt time.Duration) []string {
+	speed := s.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	s.advance(time.Duration(float64(dt) * speed))
+	var events []string
+	if len(s.events) > 0 {
+		events = append(events, s.events...)
+	}
+	s.events = s.events[:0]
+	return events
+}
+
+func (s *Sprite) advance(dt time.Duration) {
+	if len(s.Frames) == 0 {
 		return
 	}
+	s.untilNextFrame -= dt
+	for steps := 0; s.untilNextFrame <= 0 && !s.Ended(); steps++ {
+		if s.looping() {
+			loop := s.loopDuration()
+			if loop <= 0 {
+				// None of the looped frames have a duration so the time can never be used up.
+				// Limit the number of steps so that they don't loop forever.
+				if steps >= 2*len(s.Frames) {
+					s.untilNextFrame = 0
+					return
+				}
+			} else if s.untilNextFrame <= -loop {
+				// Skip the whole loops, which would end up back on the current frame.
+				s.untilNextFrame += -s.untilNextFrame / loop * loop
+			}
+		}
+		s.frame = s.nextFrame()
+		s.untilNextFrame += s.Frames[s.frame].Duration
+		s.enter()
+	}
+}
+
+// looping returns true if the sprite is repeating its frames, i.e. it loops and the current
+// frame is in the loop range, if there is one.
+func (s *Sprite) looping() bool {
+	if !s.Loop {
+		return false
+	}
+	return !s.loopRange || (s.frame >= s.loopFrom && s.frame <= s.loopTo)
+}
+
+// loopDuration returns how long it takes to play the repeated frames once.
+func (s *Sprite) loopDuration() time.Duration {
+	first, last := 0, len(s.Frames)-1
+	if s.loopRange {
+		first, last = s.loopFrom, s.loopTo
+	}
+	var d time.Duration
+	for i := first; i <= last; i++ {
+		d += s.Frames[i].Duration
+	}
+	if s.Mode == PlayPingPong && last > first {
+		// Every frame is played twice per loop except for the ones it turns around at.
+		d = 2*d - s.Frames[first].Duration - s.Frames[last].Duration
+	}
+	return d
+}
 
-	if s.untilNextFrame <= 0 {
-		s.frame++
-		if s.Loop && s.frame >= len(s.Frames) {
-			s.frame = 0
+func (s *Sprite) nextFrame() int {
+	if s.dir == 0 {
+		s.dir = s.startDir()
+	}
+	first, last := 0, len(s.Frames)-1
+	if s.Loop && s.loopRange {
+		first, last = s.loopFrom, s.loopTo
+	}
+	next := s.frame + s.dir
+	switch {
+	case s.dir > 0 && next > last:
+		if s.Mode == PlayPingPong {
+			s.dir = -1
+			return clamp(s.frame-1, first, last)
 		}
-		s.untilNextFrame = s.Frames[s.frame].Duration
+		return first
+	case s.dir < 0 && next < first:
+		if s.Mode == PlayPingPong {
+			s.dir = 1
+			return clamp(s.frame+1, first, last)
+		}
+		return last
+	}
+	return next
+}
+
+func (s *Sprite) startDir() int {
+	if s.Mode == PlayReverse {
+		return -1
+	}
+	return 1
+}
+
+func (s *Sprite) enter() {
+	s.events = append(s.events, s.Frames[s.frame].Events...)
+	if s.OnFrame != nil {
+		s.OnFrame(s.frame)
+	}
+}
+
+func clamp(i, min, max int) int {
+	if i < min {
+		return min
 	}
+	if i > max {
+		return max
+	}
+	return i
 }
 
-// Draw the current frame to dst with the given options.
-func (s *Sprite) Draw(dst *ebiten.Image, opts *ebiten.DrawImageOptions) {
+// Draw the current frame to dst placed according to t.
+func (s *Sprite) Draw(dst *ebiten.Image, t Transform) {
 	if len(s.Frames) == 0 {
 		return
 	}
 
-	dst.DrawImage(s.Frames[s.frame].Img, opts)
+	f := &s.Frames[s.frame]
+	if f.Img == nil {
+		return
+	}
+
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(f.Offset.XY())
+	opts.GeoM.Concat(t.GeoM(s))
+	opts.ColorM = t.ColorM()
+	src := f.Src
+	opts.SourceRect = &src
+	dst.DrawImage(f.Img, &opts)
 }
@@ -0,0 +1,130 @@
+package sprite
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestSprite returns a Sprite with frames of the given durations in milliseconds.
+func newTestSprite(ms ...int) *Sprite {
+	d := &Desc{Name: "test"}
+	for _, m := range ms {
+		d.Frames = append(d.Frames, FrameDesc{Duration: time.Duration(m) * time.Millisecond})
+	}
+	return &Sprite{Desc: d}
+}
+
+func TestSeek(t *testing.T) {
+	cases := []struct {
+		name  string
+		ms    []int
+		loop  bool
+		mode  PlayMode
+		loopR []int
+		seek  time.Duration
+		frame int
+	}{
+		{"start", []int{100, 100, 100, 100}, true, PlayForward, nil, 0, 0},
+		{"within first loop", []int{100, 100, 100, 100}, true, PlayForward, nil, 250 * time.Millisecond, 2},
+		{"after loops", []int{100, 100, 100, 100}, true, PlayForward, nil, 1050 * time.Millisecond, 2},
+		{"exact loop", []int{100, 100, 100, 100}, true, PlayForward, nil, 800 * time.Millisecond, 0},
+		{"uneven frames", []int{50, 150, 100}, true, PlayForward, nil, 3*300*time.Millisecond + 120*time.Millisecond, 1},
+		{"reverse", []int{100, 100, 100, 100}, true, PlayReverse, nil, 1050 * time.Millisecond, 1},
+		// 0 1 2 3 2 1 | 0 1 ...
+		{"ping pong", []int{100, 100, 100, 100}, true, PlayPingPong, nil, 700 * time.Millisecond, 1},
+		{"ping pong back", []int{100, 100, 100, 100}, true, PlayPingPong, nil, 1650 * time.Millisecond, 2},
+		// 0 1 | 2 3 4 | 2 3 4 ...
+		{"loop range", []int{100, 100, 100, 100, 100, 100}, true, PlayForward, []int{2, 4}, 1000 * time.Millisecond, 4},
+		{"not looping", []int{100, 100, 100, 100}, false, PlayForward, nil, 1050 * time.Millisecond, 3},
+	}
+	for _, c := range cases {
+		s := newTestSprite(c.ms...)
+		s.Loop = c.loop
+		s.Mode = c.mode
+		if c.loopR != nil {
+			s.SetLoopRange(c.loopR[0], c.loopR[1])
+		}
+		s.Seek(c.seek)
+		if got := s.Frame(); got != c.frame {
+			t.Errorf("%s: Seek(%v) frame = %d, want %d", c.name, c.seek, got, c.frame)
+		}
+	}
+}
+
+func TestSeekThenUpdate(t *testing.T) {
+	s := newTestSprite(100, 100, 100, 100)
+	s.Loop = true
+	s.Seek(1050 * time.Millisecond)
+	// 50ms is left on frame 2.
+	s.Update(49 * time.Millisecond)
+	if got := s.Frame(); got != 2 {
+		t.Errorf("frame = %d, want 2", got)
+	}
+	s.Update(time.Millisecond)
+	if got := s.Frame(); got != 3 {
+		t.Errorf("frame = %d, want 3", got)
+	}
+}
+
+func TestUpdateLargeDt(t *testing.T) {
+	s := newTestSprite(100, 100, 100, 100)
+	s.Loop = true
+	s.Start()
+	s.Update(time.Hour + 250*time.Millisecond)
+	if got := s.Frame(); got != 2 {
+		t.Errorf("frame = %d, want 2", got)
+	}
+	// The rest of frame 2 shouldn't have been used up by the large dt.
+	s.Update(40 * time.Millisecond)
+	if got := s.Frame(); got != 2 {
+		t.Errorf("frame = %d, want 2", got)
+	}
+	s.Update(20 * time.Millisecond)
+	if got := s.Frame(); got != 3 {
+		t.Errorf("frame = %d, want 3", got)
+	}
+}
+
+func TestUpdateLargeDtEnds(t *testing.T) {
+	s := newTestSprite(100, 100, 100)
+	s.Start()
+	s.Update(time.Hour)
+	if got := s.Frame(); got != 2 || !s.Ended() {
+		t.Errorf("frame = %d, ended = %v, want 2, true", got, s.Ended())
+	}
+}
+
+func TestUpdateZeroDurationLoop(t *testing.T) {
+	s := newTestSprite(0, 0, 0)
+	s.Loop = true
+	s.Start()
+	// This only needs to return.
+	s.Update(time.Second)
+	s.Update(time.Second)
+}
+
+func TestUpdateEvents(t *testing.T) {
+	s := newTestSprite(100, 100, 100)
+	s.Frames[1].Events = []string{"hit"}
+	s.Frames[2].Events = []string{"end"}
+	s.Start()
+	events := s.Update(250 * time.Millisecond)
+	if len(events) != 2 || events[0] != "hit" || events[1] != "end" {
+		t.Fatalf("events = %v, want [hit end]", events)
+	}
+}
+
+func TestUpdateEventsKept(t *testing.T) {
+	s := newTestSprite(100, 100)
+	s.Frames[0].Events = []string{"start"}
+	s.Frames[1].Events = []string{"second"}
+	s.Start()
+	events := s.Update(0)
+	// Entering frames again mustn't change the events already returned.
+	s.Start()
+	s.SetFrame(1)
+	s.Update(150 * time.Millisecond)
+	if len(events) != 1 || events[0] != "start" {
+		t.Errorf("events = %v, want [start]", events)
+	}
+}
@@ -0,0 +1,287 @@
+//go:build ignore
+// +build ignore
+
+// gen writes the PSD files used by the sprite package's tests. Run it from this directory
+// with "go run gen.go".
+//
+// The files are RGB with uncompressed channels and only the layer data the tests need.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"io/ioutil"
+	"log"
+	"unicode/utf16"
+)
+
+const (
+	flagHidden = 0x02
+	// flagNewer is set by Photoshop 5 and later.
+	flagNewer = 0x08
+	// flagFolder marks pixel data that doesn't affect the document's appearance.
+	flagFolder = 0x10
+
+	sectionFolder  = 1
+	sectionDivider = 3
+)
+
+type layer struct {
+	name     string
+	x, y     int
+	w, h     int
+	pixels   []color.RGBA
+	hidden   bool
+	opacity  byte
+	clipping bool
+	// children is not nil for folders, in which case the other fields except name and
+	// hidden are ignored.
+	children []*layer
+}
+
+func folder(name string, children ...*layer) *layer {
+	return &layer{name: name, children: children}
+}
+
+func hidden(l *layer) *layer {
+	l.hidden = true
+	return l
+}
+
+// fill returns a layer with its top left at (x, y) that's filled with c.
+func fill(name string, x, y, w, h int, c color.RGBA) *layer {
+	l := &layer{name: name, x: x, y: y, w: w, h: h, opacity: 0xff}
+	for i := 0; i < w*h; i++ {
+		l.pixels = append(l.pixels, c)
+	}
+	return l
+}
+
+var (
+	red   = color.RGBA{0xff, 0, 0, 0xff}
+	blue  = color.RGBA{0, 0, 0xff, 0xff}
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	black = color.RGBA{0, 0, 0, 0xff}
+)
+
+// img is a 2x1 Img layer that covers the whole document.
+func img(c color.RGBA) *layer {
+	return fill("Img", 0, 0, 2, 1, c)
+}
+
+func main() {
+	errors := []*layer{
+		folder("Sprite missing",
+			folder("Frame 2 100ms", img(red)),
+			folder("Frame 0 100ms", img(red))),
+		folder("Sprite duplicate",
+			folder("Frame 0 100ms", img(red)),
+			folder("Frame 0 100ms", img(red))),
+		folder("Sprite noimg",
+			folder("Frame 0 100ms", fill("Point anchor", 0, 0, 1, 1, red))),
+		folder("Sprite twoimg",
+			folder("Frame 0 100ms", img(red), img(blue))),
+	}
+
+	black50 := fill("black", 0, 0, 2, 1, black)
+	black50.opacity = 0x80
+	clipped := fill("blue", 0, 0, 2, 1, blue)
+	clipped.clipping = true
+	composite := []*layer{
+		hidden(folder("Sprite composite",
+			folder("Frame 0 100ms", folder("Img",
+				hidden(fill("blue", 0, 0, 2, 1, blue)),
+				fill("red", 0, 0, 2, 1, red))),
+			folder("Frame 1 100ms", folder("Img",
+				black50,
+				fill("white", 0, 0, 2, 1, white))),
+			folder("Frame 2 100ms", folder("Img",
+				clipped,
+				fill("red", 0, 0, 1, 1, red))),
+			folder("Frame 3 100ms", hidden(img(red))),
+			folder("Frame 4 100ms", img(red)),
+			folder("Frame 5 100ms", hidden(folder("Img", fill("red", 0, 0, 2, 1, red)))))),
+	}
+
+	write("errors.psd", 2, 1, errors)
+	write("composite.psd", 2, 1, composite)
+}
+
+func write(name string, w, h int, layers []*layer) {
+	if err := ioutil.WriteFile(name, psd(w, h, layers), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) put(data ...interface{}) {
+	for _, d := range data {
+		if s, ok := d.(string); ok {
+			w.WriteString(s)
+			continue
+		}
+		binary.Write(w, binary.BigEndian, d)
+	}
+}
+
+// section writes the length of the data followed by the data.
+func (w *writer) section(data []byte) {
+	w.put(uint32(len(data)))
+	w.Write(data)
+}
+
+func psd(width, height int, layers []*layer) []byte {
+	var records, channels writer
+	flat := flatten(layers)
+	for _, r := range flat {
+		records.Write(r.record(&channels))
+	}
+
+	var layerInfo writer
+	layerInfo.put(int16(len(flat)))
+	layerInfo.Write(records.Bytes())
+	layerInfo.Write(channels.Bytes())
+	if layerInfo.Len()%2 != 0 {
+		layerInfo.put(byte(0))
+	}
+
+	var layerMask writer
+	layerMask.section(layerInfo.Bytes())
+	// No global layer mask
+	layerMask.put(uint32(0))
+
+	var out writer
+	out.put("8BPS", uint16(1), [6]byte{})
+	// channels, height, width, depth, RGB color mode
+	out.put(uint16(3), uint32(height), uint32(width), uint16(8), uint16(3))
+	// No color mode data or image resources
+	out.put(uint32(0), uint32(0))
+	out.section(layerMask.Bytes())
+	// The merged image, raw and left empty.
+	out.put(uint16(0))
+	out.Write(make([]byte, 3*width*height))
+	return out.Bytes()
+}
+
+type kind int
+
+const (
+	kindLayer kind = iota
+	kindFolder
+	kindDivider
+)
+
+type record struct {
+	kind kind
+	*layer
+}
+
+// flatten returns the records for layers in file order, which is bottom to top. The
+// records in a folder come after a divider and before the folder's own record.
+func flatten(layers []*layer) []record {
+	var out []record
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+		if l.children == nil {
+			out = append(out, record{kindLayer, l})
+			continue
+		}
+		out = append(out, record{kindDivider, l})
+		out = append(out, flatten(l.children)...)
+		out = append(out, record{kindFolder, l})
+	}
+	return out
+}
+
+// record returns the layer record and writes its channel data to channels.
+func (r record) record(channels *writer) []byte {
+	var w writer
+	name := r.name
+	var top, left, bottom, right int32
+	var flags byte
+	opacity := byte(0xff)
+	clipping := byte(0)
+	switch r.kind {
+	case kindLayer:
+		top, left = int32(r.y), int32(r.x)
+		bottom, right = top+int32(r.h), left+int32(r.w)
+		flags = flagNewer
+		opacity = r.opacity
+		if r.clipping {
+			clipping = 1
+		}
+	case kindFolder:
+		flags = flagNewer | flagFolder
+	case kindDivider:
+		name = "</Layer group>"
+		flags = flagNewer | flagFolder
+	}
+	if r.hidden && r.kind != kindDivider {
+		flags |= flagHidden
+	}
+
+	w.put(top, left, bottom, right, uint16(4))
+	// Alpha first, then red, green and blue
+	for _, c := range []int16{-1, 0, 1, 2} {
+		var data writer
+		// Raw data
+		data.put(uint16(0))
+		if r.kind == kindLayer {
+			for _, p := range r.pixels {
+				switch c {
+				case -1:
+					data.put(p.A)
+				case 0:
+					data.put(p.R)
+				case 1:
+					data.put(p.G)
+				case 2:
+					data.put(p.B)
+				}
+			}
+		}
+		w.put(c, uint32(data.Len()))
+		channels.Write(data.Bytes())
+	}
+	w.put("8BIM", "norm", opacity, clipping, flags, byte(0))
+
+	var extra writer
+	// No layer mask or blending ranges
+	extra.put(uint32(0), uint32(0))
+	// The name is padded to a multiple of 4 bytes
+	extra.put(byte(len(name)), name)
+	extra.Write(make([]byte, (4-(1+len(name))%4)%4))
+	extra.Write(additionalInfo("luni", unicodeName(name)))
+	if r.kind != kindLayer {
+		section := uint32(sectionFolder)
+		if r.kind == kindDivider {
+			section = sectionDivider
+		}
+		var s writer
+		s.put(section)
+		extra.Write(additionalInfo("lsct", s.Bytes()))
+	}
+	w.section(extra.Bytes())
+	return w.Bytes()
+}
+
+func unicodeName(name string) []byte {
+	var w writer
+	chars := utf16.Encode([]rune(name))
+	w.put(uint32(len(chars)), chars)
+	for w.Len()%4 != 0 {
+		w.put(byte(0))
+	}
+	return w.Bytes()
+}
+
+func additionalInfo(key string, data []byte) []byte {
+	var w writer
+	w.put("8BIM", key)
+	w.section(data)
+	return w.Bytes()
+}
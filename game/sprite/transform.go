@@ -0,0 +1,113 @@
+package sprite
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Transform describes how a Sprite is placed when it's drawn. The zero value draws the
+// sprite's top left corner at (0, 0) with no other changes.
+type Transform struct {
+	// Pos is where the Pivot is placed on the destination image.
+	Pos geo.Vec
+	// Pivot is the name of the Point to flip, scale and rotate around. The first point
+	// with that name in the current frame is used. If it is empty, or the frame doesn't
+	// have it, then the sprite's top left corner is used.
+	Pivot string
+	FlipX bool
+	FlipY bool
+	// Rotation is in radians, clockwise on screen.
+	Rotation float64
+	// Scale multiplies the sprite's size. A zero component is treated as 1.
+	Scale geo.Vec
+	// Tint is multiplied with the sprite's colors. It is ignored if nil.
+	Tint color.Color
+}
+
+// GeoM returns the matrix that takes a position relative to the sprite's top left corner
+// to its position on the destination image.
+func (t Transform) GeoM(s *Sprite) ebiten.GeoM {
+	pivot := geo.Vec0
+	if t.Pivot != "" {
+		if points := s.Points(t.Pivot); len(points) > 0 {
+			pivot = points[0]
+		}
+	}
+	scale := t.Scale
+	if scale.X == 0 {
+		scale.X = 1
+	}
+	if scale.Y == 0 {
+		scale.Y = 1
+	}
+	if t.FlipX {
+		scale.X = -scale.X
+	}
+	if t.FlipY {
+		scale.Y = -scale.Y
+	}
+
+	m := ebiten.GeoM{}
+	m.Translate(-pivot.X, -pivot.Y)
+	m.Scale(scale.XY())
+	m.Rotate(t.Rotation)
+	m.Translate(t.Pos.XY())
+	return m
+}
+
+// ColorM returns the color matrix that applies Tint.
+func (t Transform) ColorM() ebiten.ColorM {
+	m := ebiten.ColorM{}
+	if t.Tint != nil {
+		c := color.NRGBAModel.Convert(t.Tint).(color.NRGBA)
+		m.Scale(float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff, float64(c.A)/0xff)
+	}
+	return m
+}
+
+// TransformedPoints is like Points but the points are in the destination image's
+// coordinates after applying t.
+func (s *Sprite) TransformedPoints(name string, t Transform) []geo.Vec {
+	points := s.Points(name)
+	if len(points) == 0 {
+		return nil
+	}
+	m := t.GeoM(s)
+	transformed := make([]geo.Vec, len(points))
+	for i, p := range points {
+		transformed[i] = geo.VecXY(m.Apply(p.XY()))
+	}
+	return transformed
+}
+
+// TransformedRects is like Rects but the rectangles are in the destination image's
+// coordinates after applying t. Since a Rect can't be rotated, rotated rectangles are
+// replaced by their axis aligned bounding box.
+func (s *Sprite) TransformedRects(name string, t Transform) []geo.Rect {
+	rects := s.Rects(name)
+	if len(rects) == 0 {
+		return nil
+	}
+	m := t.GeoM(s)
+	transformed := make([]geo.Rect, len(rects))
+	for i, r := range rects {
+		corners := [4]geo.Vec{
+			geo.VecXY(r.X, r.Y),
+			geo.VecXY(r.X+r.W, r.Y),
+			geo.VecXY(r.X, r.Y+r.H),
+			geo.VecXY(r.X+r.W, r.Y+r.H),
+		}
+		min := geo.VecXY(math.Inf(1), math.Inf(1))
+		max := geo.VecXY(math.Inf(-1), math.Inf(-1))
+		for _, c := range corners {
+			x, y := m.Apply(c.XY())
+			min = geo.VecXY(math.Min(min.X, x), math.Min(min.Y, y))
+			max = geo.VecXY(math.Max(max.X, x), math.Max(max.Y, y))
+		}
+		transformed[i] = geo.RectCornersVec(min, max)
+	}
+	return transformed
+}
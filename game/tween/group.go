@@ -0,0 +1,52 @@
+package tween
+
+import "time"
+
+// Group updates Tweens together and forgets them once they're done. The zero value is an
+// empty Group ready to use.
+type Group struct {
+	tweens []*Tween
+}
+
+// Add starts updating the Tweens with the Group. They're set to their start values right
+// away so that nothing jumps before the next Update.
+func (g *Group) Add(tweens ...*Tween) {
+	for _, t := range tweens {
+		t.set(0)
+		g.tweens = append(g.tweens, t)
+	}
+}
+
+// Update advances every Tween by dt. Tweens may be added by OnDone functions.
+func (g *Group) Update(dt time.Duration) {
+	tweens := g.tweens
+	g.tweens = nil
+	var active []*Tween
+	for _, t := range tweens {
+		if !t.Update(dt) {
+			active = append(active, t)
+		}
+	}
+	g.tweens = append(active, g.tweens...)
+}
+
+// Active returns true if any of the Tweens aren't done.
+func (g *Group) Active() bool {
+	return len(g.tweens) > 0
+}
+
+// Finish jumps every Tween to its end, including any added by OnDone functions.
+func (g *Group) Finish() {
+	for len(g.tweens) > 0 {
+		tweens := g.tweens
+		g.tweens = nil
+		for _, t := range tweens {
+			t.Finish()
+		}
+	}
+}
+
+// Clear stops every Tween where it is.
+func (g *Group) Clear() {
+	g.tweens = nil
+}
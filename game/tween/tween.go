@@ -0,0 +1,108 @@
+// Package tween animates values over time.
+package tween
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/Bredgren/game1/game/util"
+	"github.com/Bredgren/geo"
+)
+
+// Tween changes a value over Duration. Each Update it calls Set with how far along it is,
+// from 0 to 1, after passing that through Ease. Nothing moves until Delay has passed, but
+// Set is still called with the start value so that delayed Tweens begin in place.
+type Tween struct {
+	Duration time.Duration
+	Delay    time.Duration
+	// Ease is linear if it's nil. Easing functions may overshoot 0 and 1, e.g.
+	// geo.EaseOutBack.
+	Ease geo.EaseFn
+	Set  func(t float64)
+	// OnDone is called once the Tween reaches the end.
+	OnDone  func()
+	elapsed time.Duration
+	done    bool
+}
+
+// New returns a Tween that calls set over the duration.
+func New(duration time.Duration, ease geo.EaseFn, set func(t float64)) *Tween {
+	return &Tween{
+		Duration: duration,
+		Ease:     ease,
+		Set:      set,
+	}
+}
+
+// Float returns a Tween that moves the value at p from one number to another.
+func Float(p *float64, from, to float64, duration time.Duration, ease geo.EaseFn) *Tween {
+	return New(duration, ease, func(t float64) {
+		*p = geo.Lerp(from, to, t)
+	})
+}
+
+// Vec returns a Tween that moves the value at p from one vector to another.
+func Vec(p *geo.Vec, from, to geo.Vec, duration time.Duration, ease geo.EaseFn) *Tween {
+	return New(duration, ease, func(t float64) {
+		*p = geo.VecXY(geo.Lerp(from.X, to.X, t), geo.Lerp(from.Y, to.Y, t))
+	})
+}
+
+// Color returns a Tween that changes the value at p from one color to another.
+func Color(p *color.Color, from, to color.Color, duration time.Duration, ease geo.EaseFn) *Tween {
+	return New(duration, ease, func(t float64) {
+		*p = util.LerpColor(from, to, geo.Clamp(t, 0, 1))
+	})
+}
+
+// Update advances the Tween by dt and returns true if it's done.
+func (t *Tween) Update(dt time.Duration) bool {
+	if t.done {
+		return true
+	}
+	t.elapsed += dt
+	if t.elapsed-t.Delay >= t.Duration {
+		t.Finish()
+		return true
+	}
+	progress := 0.0
+	if t.elapsed > t.Delay {
+		progress = float64(t.elapsed-t.Delay) / float64(t.Duration)
+	}
+	t.set(progress)
+	return false
+}
+
+// Finish jumps to the end of the Tween.
+func (t *Tween) Finish() {
+	if t.done {
+		return
+	}
+	t.done = true
+	t.elapsed = t.Delay + t.Duration
+	t.set(1)
+	if t.OnDone != nil {
+		t.OnDone()
+	}
+}
+
+// Restart goes back to the beginning of the Tween, including its Delay.
+func (t *Tween) Restart() {
+	t.elapsed = 0
+	t.done = false
+	t.set(0)
+}
+
+// Done returns true once the Tween has reached the end.
+func (t *Tween) Done() bool {
+	return t.done
+}
+
+func (t *Tween) set(progress float64) {
+	if t.Ease != nil {
+		progress = t.Ease(progress)
+	}
+	if t.Set != nil {
+		t.Set(progress)
+	}
+}
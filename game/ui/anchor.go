@@ -46,3 +46,10 @@ var AnchorLeft = Anchor{
 	Src: geo.VecXY(0, 0.5),
 	Dst: geo.VecXY(0, 0.5),
 }
+
+// AnchorRight aligns the right edges of the source and destination elements, and
+// vertically centers it.
+var AnchorRight = Anchor{
+	Src: geo.VecXY(1, 0.5),
+	Dst: geo.VecXY(1, 0.5),
+}
@@ -1,52 +1,183 @@
 package ui
 
 import (
+	"math"
+
 	"github.com/Bredgren/geo"
 	"github.com/hajimehoshi/ebiten"
 )
 
-// Button is a container that holds one sub-element and images for its states.
+// ButtonState is the visual state of a Button.
+type ButtonState int
+
+const (
+	// ButtonIdle is the state when nothing else applies.
+	ButtonIdle ButtonState = iota
+	// ButtonHover is the state while the mouse is over the Button.
+	ButtonHover
+	// ButtonPressed is the state while the Button is being clicked.
+	ButtonPressed
+	// ButtonDisabled is the state while the Button is Disabled.
+	ButtonDisabled
+	// ButtonFocused is the state while the Button has focus.
+	ButtonFocused
+)
+
+// buttonFallback is the state whose background is used when a state doesn't have one.
+// Focus looks like hover unless it has its own background.
+var buttonFallback = map[ButtonState]ButtonState{
+	ButtonHover:    ButtonIdle,
+	ButtonPressed:  ButtonHover,
+	ButtonDisabled: ButtonIdle,
+	ButtonFocused:  ButtonHover,
+}
+
+// Button is a container that holds one sub-element and backgrounds for its states, e.g.
+// Images or NineSlices. A state without a background uses another state's, eventually
+// falling back to ButtonIdle.
+//
+// The Button is drawn at its preferred size, positioned by Anchor within the bounds given
+// to Draw, unless Fill is set. Its preferred size is the larger of the ButtonIdle
+// background's and Element's plus Padding.
 type Button struct {
-	IdleImg     *ebiten.Image
-	HoverImg    *ebiten.Image
+	Backgrounds map[ButtonState]Drawer
 	Element     WeightedDrawer
-	IdleAnchor  Anchor
-	HoverAnchor Anchor
+	Padding     Insets
+	Anchor      Anchor
+	Fill        bool
 	Wt          float64
 	Hover       bool
-	OnClick     func()
-	lastRect    geo.Rect
+	Pressed     bool
+	Focused     bool
+	// Disabled Buttons ignore input and can't get focus.
+	Disabled bool
+	OnClick  func()
+	lastRect geo.Rect
+}
+
+// State returns the Button's current visual state.
+func (b *Button) State() ButtonState {
+	switch {
+	case b.Disabled:
+		return ButtonDisabled
+	case b.Pressed:
+		return ButtonPressed
+	case b.Focused:
+		return ButtonFocused
+	case b.Hover:
+		return ButtonHover
+	}
+	return ButtonIdle
+}
+
+// HandleEvent updates the Button's state from events sent by a Root and calls OnClick
+// when it is clicked.
+func (b *Button) HandleEvent(e Event) bool {
+	if b.Disabled {
+		b.Hover, b.Pressed = false, false
+		return true
+	}
+	switch e.Type {
+	case EventEnter:
+		b.Hover = true
+	case EventLeave:
+		b.Hover = false
+	case EventPress:
+		b.Pressed = true
+	case EventRelease:
+		b.Pressed = false
+	case EventClick:
+		b.Activate()
+	}
+	return true
+}
+
+// SetFocus gives or takes away focus. Taking focus away also clears Hover so that the
+// Button doesn't stay highlighted when focus moves away from under the mouse.
+func (b *Button) SetFocus(focused bool) {
+	b.Focused = focused
+	if !focused {
+		b.Hover = false
+	}
+}
+
+// CanFocus returns true unless the Button is Disabled.
+func (b *Button) CanFocus() bool {
+	return !b.Disabled
+}
+
+// Rect returns the rectangle that the last call to Draw put the button at.
+func (b *Button) Rect() geo.Rect {
+	return b.lastRect
 }
 
-// Update sets b.Hover according to the current mouse position and the position that
-// the last call to Draw put the button at.
-func (b *Button) Update() {
-	mousePos := geo.VecXY(geo.I2F2(ebiten.CursorPosition()))
-	b.Hover = b.lastRect.CollidePoint(mousePos.XY())
+// Activate calls OnClick if it is set and the Button isn't Disabled.
+func (b *Button) Activate() {
+	if b.OnClick != nil && !b.Disabled {
+		b.OnClick()
+	}
 }
 
 // Draw draws the Button to the image within bounds.
 func (b *Button) Draw(dst *ebiten.Image, bounds geo.Rect) {
-	opts := ebiten.DrawImageOptions{}
-	var imgBounds geo.Rect
-	var topLeft geo.Vec
-	if b.Hover {
-		imgBounds = geo.RectWH(geo.I2F2(b.HoverImg.Size()))
-		topLeft = b.HoverAnchor.TopLeft(imgBounds, bounds)
-		opts.GeoM.Translate(topLeft.XY())
-		dst.DrawImage(b.HoverImg, &opts)
-	} else {
-		imgBounds = geo.RectWH(geo.I2F2(b.IdleImg.Size()))
-		topLeft = b.IdleAnchor.TopLeft(imgBounds, bounds)
-		opts.GeoM.Translate(topLeft.XY())
-		dst.DrawImage(b.IdleImg, &opts)
-	}
-	imgBounds.SetTopLeft(topLeft.XY())
-	b.lastRect = imgBounds
+	rect := bounds
+	if !b.Fill {
+		size := b.PreferredSize()
+		rect = geo.RectWH(math.Min(size.X, bounds.W), math.Min(size.Y, bounds.H))
+		rect.SetTopLeft(b.Anchor.TopLeft(rect, bounds).XY())
+	}
+	b.lastRect = rect
 
+	if bg := b.background(); bg != nil {
+		bg.Draw(dst, rect)
+	}
 	if b.Element != nil {
-		b.Element.Draw(dst, imgBounds)
+		b.Element.Draw(dst, b.Padding.Shrink(rect))
+	}
+}
+
+func (b *Button) background() Drawer {
+	state := b.State()
+	for {
+		if bg := b.Backgrounds[state]; bg != nil {
+			return bg
+		}
+		if state == ButtonIdle {
+			return nil
+		}
+		state = buttonFallback[state]
+	}
+}
+
+// MinSize returns the larger of the idle background's and Element's minimum sizes.
+func (b *Button) MinSize() geo.Vec {
+	return b.size(minSize)
+}
+
+// PreferredSize returns the larger of the idle background's and Element's preferred
+// sizes.
+func (b *Button) PreferredSize() geo.Vec {
+	return b.size(preferredSize)
+}
+
+func (b *Button) size(fn func(e Drawer) geo.Vec) geo.Vec {
+	var size geo.Vec
+	if bg := b.Backgrounds[ButtonIdle]; bg != nil {
+		size = fn(bg)
+	}
+	if b.Element != nil {
+		e := b.Padding.Grow(fn(b.Element))
+		size = geo.VecXY(math.Max(size.X, e.X), math.Max(size.Y, e.Y))
+	}
+	return size
+}
+
+// Children returns the Button's sub-element.
+func (b *Button) Children() []WeightedDrawer {
+	if b.Element == nil {
+		return nil
 	}
+	return []WeightedDrawer{b.Element}
 }
 
 // Weight returns the relative weight for allocating space within a container.
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Checkbox is an element that toggles between checked and unchecked when it is clicked or
+// activated. It draws a box, On or Off depending on Checked, at BoxSize and an optional
+// Label to its right separated by Gap. Highlight is drawn behind everything while the
+// Checkbox has focus or the mouse is over it.
+type Checkbox struct {
+	Checked   bool
+	OnChange  func(checked bool)
+	On        Drawer
+	Off       Drawer
+	Highlight Drawer
+	BoxSize   geo.Vec
+	Label     WeightedDrawer
+	Gap       float64
+	Wt        float64
+	Disabled  bool
+	Hover     bool
+	Focused   bool
+	lastRect  geo.Rect
+}
+
+// HandleEvent toggles the Checkbox when it is clicked.
+func (c *Checkbox) HandleEvent(e Event) bool {
+	if c.Disabled {
+		c.Hover = false
+		return true
+	}
+	switch e.Type {
+	case EventEnter:
+		c.Hover = true
+	case EventLeave:
+		c.Hover = false
+	case EventClick:
+		c.Activate()
+	}
+	return true
+}
+
+// SetChecked sets Checked and calls OnChange if it changed.
+func (c *Checkbox) SetChecked(checked bool) {
+	if checked == c.Checked {
+		return
+	}
+	c.Checked = checked
+	if c.OnChange != nil {
+		c.OnChange(checked)
+	}
+}
+
+// SetFocus gives or takes away focus.
+func (c *Checkbox) SetFocus(focused bool) {
+	c.Focused = focused
+	if !focused {
+		c.Hover = false
+	}
+}
+
+// CanFocus returns true unless the Checkbox is Disabled.
+func (c *Checkbox) CanFocus() bool {
+	return !c.Disabled
+}
+
+// Rect returns the rectangle that the last call to Draw put the Checkbox at.
+func (c *Checkbox) Rect() geo.Rect {
+	return c.lastRect
+}
+
+// Activate toggles the Checkbox unless it's Disabled.
+func (c *Checkbox) Activate() {
+	if !c.Disabled {
+		c.SetChecked(!c.Checked)
+	}
+}
+
+// Draw draws the Checkbox to the image within bounds.
+func (c *Checkbox) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	c.lastRect = bounds
+	if (c.Focused || c.Hover) && c.Highlight != nil {
+		c.Highlight.Draw(dst, bounds)
+	}
+
+	box := geo.RectWH(c.BoxSize.XY())
+	box.SetTopLeft(bounds.X, bounds.Y+(bounds.H-box.H)/2)
+	if c.Checked && c.On != nil {
+		c.On.Draw(dst, box)
+	} else if !c.Checked && c.Off != nil {
+		c.Off.Draw(dst, box)
+	}
+
+	if c.Label != nil {
+		left := box.W + c.Gap
+		c.Label.Draw(dst, geo.RectXYWH(bounds.X+left, bounds.Y, math.Max(bounds.W-left, 0), bounds.H))
+	}
+}
+
+// MinSize returns the size of the box and the Label's minimum size side by side.
+func (c *Checkbox) MinSize() geo.Vec {
+	return c.size(minSize)
+}
+
+// PreferredSize returns the size of the box and the Label's preferred size side by side.
+func (c *Checkbox) PreferredSize() geo.Vec {
+	return c.size(preferredSize)
+}
+
+func (c *Checkbox) size(fn func(e Drawer) geo.Vec) geo.Vec {
+	size := c.BoxSize
+	if c.Label != nil {
+		label := fn(c.Label)
+		size = geo.VecXY(size.X+c.Gap+label.X, math.Max(size.Y, label.Y))
+	}
+	return size
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (c *Checkbox) Weight() float64 {
+	return c.Wt
+}
@@ -5,63 +5,100 @@ import (
 	"github.com/hajimehoshi/ebiten"
 )
 
-// VerticalContainer is a container that orders each of its elements vertically. Their
-// widths will be the full width of the bounding rectangle given to Draw and their heights
-// will be determined by their Weights relative to the other elements.
+// VerticalContainer is a container that orders each of its elements vertically. Elements
+// with no Weight are their preferred height and the remaining height is divided between
+// the others according to their Weights. Their widths are determined by Align.
 type VerticalContainer struct {
 	Elements []WeightedDrawer
 	Wt       float64
+	// Padding is the space between the edges of the container and its elements.
+	Padding Insets
+	// Gap is the space between each element.
+	Gap   float64
+	Align CrossAlign
 }
 
 // Draw draws all of the container's elements to dst within bounds.
 func (v *VerticalContainer) Draw(dst *ebiten.Image, bounds geo.Rect) {
-	totalWeight := 0.0
-	for _, e := range v.Elements {
-		totalWeight += e.Weight()
+	for i, r := range v.line().layout(bounds) {
+		v.Elements[i].Draw(dst, r)
 	}
-	heights := make([]float64, len(v.Elements))
-	for i, e := range v.Elements {
-		heights[i] = e.Weight() / totalWeight * bounds.H
-	}
-	subBounds := bounds
-	for i, h := range heights {
-		subBounds.H = h
-		v.Elements[i].Draw(dst, subBounds)
-		subBounds.Y += h
+}
+
+// MinSize returns the size needed to fit every element at its minimum size.
+func (v *VerticalContainer) MinSize() geo.Vec {
+	return v.line().size(minSize)
+}
+
+// PreferredSize returns the size needed to fit every element at its preferred size.
+func (v *VerticalContainer) PreferredSize() geo.Vec {
+	return v.line().size(preferredSize)
+}
+
+func (v *VerticalContainer) line() line {
+	return line{
+		elements: v.Elements,
+		padding:  v.Padding,
+		gap:      v.Gap,
+		align:    v.Align,
+		vertical: true,
 	}
 }
 
+// Children returns the container's elements.
+func (v *VerticalContainer) Children() []WeightedDrawer {
+	return v.Elements
+}
+
 // Weight returns the relative weight of the container so that containers may be nested.
 func (v *VerticalContainer) Weight() float64 {
 	return v.Wt
 }
 
-// HorizontalContainer is a container that orders each of its elements horizontally. Their
-// heights will be the full height of the bounding rectangle given to Draw and their widths
-// will be determined by their Weights relative to the other elements.
+// HorizontalContainer is a container that orders each of its elements horizontally.
+// Elements with no Weight are their preferred width and the remaining width is divided
+// between the others according to their Weights. Their heights are determined by Align.
 type HorizontalContainer struct {
 	Elements []WeightedDrawer
 	Wt       float64
+	// Padding is the space between the edges of the container and its elements.
+	Padding Insets
+	// Gap is the space between each element.
+	Gap   float64
+	Align CrossAlign
 }
 
 // Draw draws all of the container's elements to dst within bounds.
 func (h *HorizontalContainer) Draw(dst *ebiten.Image, bounds geo.Rect) {
-	totalWeight := 0.0
-	for _, e := range h.Elements {
-		totalWeight += e.Weight()
+	for i, r := range h.line().layout(bounds) {
+		h.Elements[i].Draw(dst, r)
 	}
-	widths := make([]float64, len(h.Elements))
-	for i, e := range h.Elements {
-		widths[i] = e.Weight() / totalWeight * bounds.W
-	}
-	subBounds := bounds
-	for i, w := range widths {
-		subBounds.W = w
-		h.Elements[i].Draw(dst, subBounds)
-		subBounds.X += w
+}
+
+// MinSize returns the size needed to fit every element at its minimum size.
+func (h *HorizontalContainer) MinSize() geo.Vec {
+	return h.line().size(minSize)
+}
+
+// PreferredSize returns the size needed to fit every element at its preferred size.
+func (h *HorizontalContainer) PreferredSize() geo.Vec {
+	return h.line().size(preferredSize)
+}
+
+func (h *HorizontalContainer) line() line {
+	return line{
+		elements: h.Elements,
+		padding:  h.Padding,
+		gap:      h.Gap,
+		align:    h.Align,
 	}
 }
 
+// Children returns the container's elements.
+func (h *HorizontalContainer) Children() []WeightedDrawer {
+	return h.Elements
+}
+
 // Weight returns the relative weight of the container so that containers may be nested.
 func (h *HorizontalContainer) Weight() float64 {
 	return h.Wt
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+	"golang.org/x/image/font"
+)
+
+const (
+	cyclePrev = "<"
+	cycleNext = ">"
+)
+
+// Cycle is an element for choosing one of a list of Options, e.g. a screen resolution. It
+// shows the current option between arrows. Clicking the left arrow, or pressing left while
+// it has focus, chooses the previous option; clicking anywhere else, activating it or
+// pressing right chooses the next one. The options wrap around.
+type Cycle struct {
+	Options  []string
+	Index    int
+	OnChange func(index int)
+	Face     font.Face
+	Color    color.Color
+	// Highlight is drawn behind the Cycle while it has focus or the mouse is over it.
+	Highlight Drawer
+	// Gap is the space between the arrows and the option.
+	Gap      float64
+	Wt       float64
+	Disabled bool
+	Hover    bool
+	Focused  bool
+	lastRect geo.Rect
+}
+
+// Selected returns the current option, or "" if there are none.
+func (c *Cycle) Selected() string {
+	if c.Index < 0 || c.Index >= len(c.Options) {
+		return ""
+	}
+	return c.Options[c.Index]
+}
+
+// SetIndex chooses the option at index i, wrapping around, and calls OnChange if it
+// changed.
+func (c *Cycle) SetIndex(i int) {
+	n := len(c.Options)
+	if n == 0 {
+		return
+	}
+	i = ((i % n) + n) % n
+	if i == c.Index {
+		return
+	}
+	c.Index = i
+	if c.OnChange != nil {
+		c.OnChange(i)
+	}
+}
+
+// HandleEvent changes the option when the Cycle is clicked.
+func (c *Cycle) HandleEvent(e Event) bool {
+	if c.Disabled {
+		c.Hover = false
+		return true
+	}
+	switch e.Type {
+	case EventEnter:
+		c.Hover = true
+	case EventLeave:
+		c.Hover = false
+	case EventClick:
+		if e.Pos.X < c.lastRect.X+c.text(cyclePrev).PreferredSize().X+c.Gap {
+			c.SetIndex(c.Index - 1)
+		} else {
+			c.SetIndex(c.Index + 1)
+		}
+	}
+	return true
+}
+
+// HandleDirection chooses the previous option with left and the next one with right.
+func (c *Cycle) HandleDirection(dir Direction) bool {
+	switch dir {
+	case Left:
+		c.SetIndex(c.Index - 1)
+	case Right:
+		c.SetIndex(c.Index + 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// SetFocus gives or takes away focus.
+func (c *Cycle) SetFocus(focused bool) {
+	c.Focused = focused
+	if !focused {
+		c.Hover = false
+	}
+}
+
+// CanFocus returns true unless the Cycle is Disabled or has no options.
+func (c *Cycle) CanFocus() bool {
+	return !c.Disabled && len(c.Options) > 0
+}
+
+// Rect returns the rectangle that the last call to Draw put the Cycle at.
+func (c *Cycle) Rect() geo.Rect {
+	return c.lastRect
+}
+
+// Activate chooses the next option unless the Cycle is Disabled.
+func (c *Cycle) Activate() {
+	if !c.Disabled {
+		c.SetIndex(c.Index + 1)
+	}
+}
+
+// Draw draws the Cycle to the image within bounds. The arrows are at the edges of bounds
+// and the option is centered between them.
+func (c *Cycle) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	c.lastRect = bounds
+	if (c.Focused || c.Hover) && c.Highlight != nil {
+		c.Highlight.Draw(dst, bounds)
+	}
+
+	prev, next := c.text(cyclePrev), c.text(cycleNext)
+	prev.Anchor, next.Anchor = AnchorLeft, AnchorRight
+	prev.Draw(dst, bounds)
+	next.Draw(dst, bounds)
+
+	left := prev.PreferredSize().X + c.Gap
+	right := next.PreferredSize().X + c.Gap
+	option := c.text(c.Selected())
+	option.Anchor = AnchorCenter
+	option.Ellipsis = true
+	option.Draw(dst, geo.RectXYWH(bounds.X+left, bounds.Y, math.Max(bounds.W-left-right, 0), bounds.H))
+}
+
+// MinSize returns the size of the arrows with room for an ellipsis between them.
+func (c *Cycle) MinSize() geo.Vec {
+	t := c.text("")
+	t.Ellipsis = true
+	return c.size(t.MinSize())
+}
+
+// PreferredSize returns the size of the arrows with room for the widest option between
+// them.
+func (c *Cycle) PreferredSize() geo.Vec {
+	var widest geo.Vec
+	for _, o := range c.Options {
+		s := c.text(o).PreferredSize()
+		widest = geo.VecXY(math.Max(widest.X, s.X), math.Max(widest.Y, s.Y))
+	}
+	return c.size(widest)
+}
+
+func (c *Cycle) size(option geo.Vec) geo.Vec {
+	prev, next := c.text(cyclePrev).PreferredSize(), c.text(cycleNext).PreferredSize()
+	return geo.VecXY(prev.X+next.X+2*c.Gap+option.X, math.Max(option.Y, math.Max(prev.Y, next.Y)))
+}
+
+func (c *Cycle) text(s string) *Text {
+	return &Text{Text: s, Face: c.Face, Color: c.Color}
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (c *Cycle) Weight() float64 {
+	return c.Wt
+}
@@ -0,0 +1,860 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+	"golang.org/x/image/font"
+)
+
+// itemPlaceholder is replaced by each item in a repeated element's template.
+const itemPlaceholder = "{item}"
+
+// Def describes a tree of elements, usually decoded from a JSON file with ParseDef. Root
+// is the ElementDef of the top element. Styles are named sets of ElementDef properties,
+// an element that names one with its "style" property uses the style's properties for any
+// that it doesn't set itself. Styles that the Def doesn't have come from the Theme it's
+// built with.
+//
+// Names in a Def, e.g. of callbacks and fonts, are connected to the game by the Bindings
+// given to Build.
+type Def struct {
+	Styles map[string]json.RawMessage `json:"styles"`
+	Root   json.RawMessage            `json:"root"`
+}
+
+// ElementDef describes one element in a Def. Which properties are used depends on Type:
+//   - "vertical" and "horizontal" are VerticalContainers and HorizontalContainers of
+//     Elements, using Padding, Gap and Align.
+//   - "scroll" is a ScrollContainer of Element, using Height, BarWidth, Bar and Thumb.
+//   - "button" is a Button containing Element, using Backgrounds, Padding, Anchor, Fill,
+//     Disabled and OnClick.
+//   - "text" is a Text, using Text or TextSource, Color or ColorSource, Font, Size,
+//     Anchor, Wrap, TextAlign, LineSpacing and Ellipsis.
+//   - "image" is an Image of the image named Img, or if that's empty then of a solid Color
+//     that is Width by Height pixels, using Mode and Anchor.
+//   - "slider" is a Slider of the number named Value, using Min, Max, Step, Width,
+//     Height, Track, Thumb, FocusedThumb and ThumbSize.
+//   - "checkbox" is a Checkbox of the bool named Value, labelled by Element, using Width
+//     and Height for the size of the box, On, Off, Highlight and Gap.
+//   - "cycle" is a Cycle of the string named Value choosing from the list named Items,
+//     using Font, Size, Color, Highlight and Gap.
+//   - "textinput" is a TextInput of the string named Value, which is set as the text is
+//     typed, using Font, Size, Color, Width, MaxLen, Padding, OnSubmit and Backgrounds
+//     "idle", "focused" and "editing".
+//   - "repeat" is replaced by a copy of Template for each item in the list named Items,
+//     with "{item}" in the template replaced by the item. It may only be used in Elements.
+//
+// Every element uses ID, Style and Wt.
+type ElementDef struct {
+	Type string `json:"type"`
+	// ID names the element so that the game can find it with Tree.ByID.
+	ID    string  `json:"id"`
+	Style string  `json:"style"`
+	Wt    float64 `json:"wt"`
+	// Anchor is either the name of a predefined Anchor, "center", "left" or "right", or an
+	// object with "src", "dst" and "offset" fields that are [x, y] arrays.
+	Anchor  json.RawMessage `json:"anchor"`
+	Fill    bool            `json:"fill"`
+	Padding Insets          `json:"padding"`
+	Gap     float64         `json:"gap"`
+	// Align is "stretch", "start", "center" or "end", see CrossAlign.
+	Align string `json:"align"`
+
+	Text string `json:"text"`
+	// TextSource is the name of a text binding that sets the Text each Tree.Refresh.
+	TextSource string `json:"textSource"`
+	// Color is "#rrggbb", "#rrggbbaa", "black", "white", "transparent" or the name of one
+	// of the Theme's colors after an "@", e.g. "@text".
+	Color string `json:"color"`
+	// ColorSource is the name of a color binding that sets the Color each Tree.Refresh.
+	ColorSource string  `json:"colorSource"`
+	Font        string  `json:"font"`
+	Size        float64 `json:"size"`
+	Wrap        bool    `json:"wrap"`
+	// TextAlign is "left", "center" or "right".
+	TextAlign   string  `json:"textAlign"`
+	LineSpacing float64 `json:"lineSpacing"`
+	Ellipsis    bool    `json:"ellipsis"`
+
+	// Backgrounds are ElementDefs by button state, "idle", "hover", "pressed", "disabled"
+	// or "focused".
+	Backgrounds map[string]json.RawMessage `json:"backgrounds"`
+	Disabled    bool                       `json:"disabled"`
+	// OnClick and OnSubmit are names of callback bindings.
+	OnClick  string `json:"onClick"`
+	OnSubmit string `json:"onSubmit"`
+
+	Img string `json:"img"`
+	// Mode is "none", "stretch", "fit" or "fill", see ScaleMode.
+	Mode   string  `json:"mode"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+
+	BarWidth float64         `json:"barWidth"`
+	Bar      json.RawMessage `json:"bar"`
+	Thumb    json.RawMessage `json:"thumb"`
+
+	// Value is the name of a number, bool or string binding, depending on Type.
+	Value        string          `json:"value"`
+	Min          float64         `json:"min"`
+	Max          float64         `json:"max"`
+	Step         float64         `json:"step"`
+	Track        json.RawMessage `json:"track"`
+	FocusedThumb json.RawMessage `json:"focusedThumb"`
+	ThumbSize    [2]float64      `json:"thumbSize"`
+	On           json.RawMessage `json:"on"`
+	Off          json.RawMessage `json:"off"`
+	Highlight    json.RawMessage `json:"highlight"`
+	MaxLen       int             `json:"maxLen"`
+
+	Element  json.RawMessage   `json:"element"`
+	Elements []json.RawMessage `json:"elements"`
+
+	Items    string          `json:"items"`
+	Template json.RawMessage `json:"template"`
+}
+
+// Bindings connect the names used in a Def to the game. A binding name may have an
+// argument after a colon, which is passed to the function, e.g. the callback
+// "remap:jump" calls Callbacks["remap"]("jump"). This is mostly useful in repeated
+// templates, e.g. "remap:{item}".
+type Bindings struct {
+	Callbacks map[string]func(arg string)
+	Texts     map[string]func(arg string) string
+	Colors    map[string]func(arg string) color.Color
+	// Lists are the items for repeated elements and cycles.
+	Lists map[string]func() []string
+	// Numbers, Bools and Strings are the values of sliders, checkboxes, cycles and text
+	// inputs. The element is set from Get when it's built and each Tree.Refresh, and it
+	// calls Set when it is changed.
+	Numbers map[string]NumberBinding
+	Bools   map[string]BoolBinding
+	Strings map[string]StringBinding
+	// Face returns the named font at a size, e.g. asset.Face.
+	Face func(name string, size float64) (font.Face, error)
+	// Image returns the named image, e.g. asset.Img.
+	Image func(name string) (*ebiten.Image, error)
+	// Theme provides styles and colors. It may be nil if the Def doesn't use them.
+	Theme *Theme
+}
+
+// NumberBinding gets and sets the number shown by a slider.
+type NumberBinding struct {
+	Get func(arg string) float64
+	Set func(arg string, v float64)
+}
+
+// BoolBinding gets and sets whether a checkbox is checked.
+type BoolBinding struct {
+	Get func(arg string) bool
+	Set func(arg string, v bool)
+}
+
+// StringBinding gets and sets the option chosen by a cycle, or the text of a text input.
+type StringBinding struct {
+	Get func(arg string) string
+	Set func(arg string, v string)
+}
+
+// ParseDef decodes a JSON Def.
+func ParseDef(data []byte) (*Def, error) {
+	var d Def
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	if len(d.Root) == 0 {
+		return nil, fmt.Errorf("no root element")
+	}
+	return &d, nil
+}
+
+// Tree is a tree of elements built from a Def.
+type Tree struct {
+	Element WeightedDrawer
+	ids     map[string]WeightedDrawer
+	texts   []textBinding
+	// values update sliders, checkboxes, cycles and text inputs from their bindings.
+	values []func()
+}
+
+// textBinding updates a Text from its sources.
+type textBinding struct {
+	text   *Text
+	source func() string
+	color  func() color.Color
+}
+
+// ByID returns the element with the given ID, or nil if there isn't one.
+func (t *Tree) ByID(id string) WeightedDrawer {
+	return t.ids[id]
+}
+
+// Refresh updates the Texts that have a TextSource or ColorSource, and the sliders,
+// checkboxes, cycles and text inputs from their values. It should be called before drawing when the
+// sources may have changed, e.g. every frame.
+func (t *Tree) Refresh() {
+	for _, update := range t.values {
+		update()
+	}
+	for _, b := range t.texts {
+		if b.source != nil {
+			b.text.Text = b.source()
+		}
+		if b.color != nil {
+			b.text.Color = b.color()
+		}
+	}
+}
+
+// Build creates the elements described by the Def. Every name used must be in b.
+func (d *Def) Build(b Bindings) (*Tree, error) {
+	builder := defBuilder{
+		def:      d,
+		bindings: b,
+		tree:     &Tree{ids: map[string]WeightedDrawer{}},
+		solid:    map[color.NRGBA]*ebiten.Image{},
+	}
+	e, err := builder.element(d.Root, "root")
+	if err != nil {
+		return nil, err
+	}
+	builder.tree.Element = e
+	builder.tree.Refresh()
+	return builder.tree, nil
+}
+
+type defBuilder struct {
+	def      *Def
+	bindings Bindings
+	tree     *Tree
+	// solid holds the images made for solid colors so that they're shared.
+	solid map[color.NRGBA]*ebiten.Image
+}
+
+// decode decodes the ElementDef in raw on top of its style.
+func (b *defBuilder) decode(raw json.RawMessage, path string) (*ElementDef, error) {
+	var style struct {
+		Style string `json:"style"`
+	}
+	if err := json.Unmarshal(raw, &style); err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	var e ElementDef
+	if style.Style != "" {
+		s, ok := b.def.Styles[style.Style]
+		if !ok {
+			s, ok = b.bindings.Theme.style(style.Style)
+		}
+		if !ok {
+			return nil, fmt.Errorf("ui %s: no style '%s'", path, style.Style)
+		}
+		if err := json.Unmarshal(s, &e); err != nil {
+			return nil, fmt.Errorf("ui style '%s': %v", style.Style, err)
+		}
+	}
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	return &e, nil
+}
+
+// element builds a single element, which can't be repeated.
+func (b *defBuilder) element(raw json.RawMessage, path string) (WeightedDrawer, error) {
+	elements, err := b.elements(raw, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) != 1 {
+		return nil, fmt.Errorf("ui %s: expected one element", path)
+	}
+	return elements[0], nil
+}
+
+// elements builds the element in raw, or the elements it repeats.
+func (b *defBuilder) elements(raw json.RawMessage, path string) ([]WeightedDrawer, error) {
+	d, err := b.decode(raw, path)
+	if err != nil {
+		return nil, err
+	}
+	if d.Type == "repeat" {
+		return b.repeat(d, path)
+	}
+
+	var e WeightedDrawer
+	switch d.Type {
+	case "vertical", "horizontal":
+		e, err = b.container(d, path)
+	case "scroll":
+		e, err = b.scroll(d, path)
+	case "button":
+		e, err = b.button(d, path)
+	case "text":
+		e, err = b.text(d, path)
+	case "image":
+		e, err = b.image(d, path)
+	case "slider":
+		e, err = b.slider(d, path)
+	case "checkbox":
+		e, err = b.checkbox(d, path)
+	case "cycle":
+		e, err = b.cycle(d, path)
+	case "textinput":
+		e, err = b.textInput(d, path)
+	default:
+		err = fmt.Errorf("ui %s: unknown type '%s'", path, d.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if d.ID != "" {
+		if _, ok := b.tree.ids[d.ID]; ok {
+			return nil, fmt.Errorf("ui %s: duplicate id '%s'", path, d.ID)
+		}
+		b.tree.ids[d.ID] = e
+	}
+	return []WeightedDrawer{e}, nil
+}
+
+func (b *defBuilder) repeat(d *ElementDef, path string) ([]WeightedDrawer, error) {
+	list, ok := b.bindings.Lists[d.Items]
+	if !ok {
+		return nil, fmt.Errorf("ui %s: no list '%s'", path, d.Items)
+	}
+	var elements []WeightedDrawer
+	for i, item := range list() {
+		// Escape the item so that it can go anywhere in a JSON string.
+		escaped, _ := json.Marshal(item)
+		escaped = escaped[1 : len(escaped)-1]
+		raw := bytes.Replace(d.Template, []byte(itemPlaceholder), escaped, -1)
+		e, err := b.element(raw, fmt.Sprintf("%s.template[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+	}
+	return elements, nil
+}
+
+func (b *defBuilder) children(d *ElementDef, path string) ([]WeightedDrawer, error) {
+	var elements []WeightedDrawer
+	for i, raw := range d.Elements {
+		e, err := b.elements(raw, fmt.Sprintf("%s.elements[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e...)
+	}
+	return elements, nil
+}
+
+// optional builds the element in raw if there is one.
+func (b *defBuilder) optional(raw json.RawMessage, path string) (WeightedDrawer, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return b.element(raw, path)
+}
+
+func (b *defBuilder) container(d *ElementDef, path string) (WeightedDrawer, error) {
+	elements, err := b.children(d, path)
+	if err != nil {
+		return nil, err
+	}
+	align, err := parseCrossAlign(d.Align)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	if d.Type == "vertical" {
+		return &VerticalContainer{
+			Elements: elements,
+			Wt:       d.Wt,
+			Padding:  d.Padding,
+			Gap:      d.Gap,
+			Align:    align,
+		}, nil
+	}
+	return &HorizontalContainer{
+		Elements: elements,
+		Wt:       d.Wt,
+		Padding:  d.Padding,
+		Gap:      d.Gap,
+		Align:    align,
+	}, nil
+}
+
+func (b *defBuilder) scroll(d *ElementDef, path string) (WeightedDrawer, error) {
+	element, err := b.element(d.Element, path+".element")
+	if err != nil {
+		return nil, err
+	}
+	bar, err := b.optional(d.Bar, path+".bar")
+	if err != nil {
+		return nil, err
+	}
+	thumb, err := b.optional(d.Thumb, path+".thumb")
+	if err != nil {
+		return nil, err
+	}
+	return &ScrollContainer{
+		Element:  element,
+		Height:   d.Height,
+		Bar:      bar,
+		Thumb:    thumb,
+		BarWidth: d.BarWidth,
+		Wt:       d.Wt,
+	}, nil
+}
+
+func (b *defBuilder) button(d *ElementDef, path string) (WeightedDrawer, error) {
+	anchor, err := parseAnchor(d.Anchor)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	element, err := b.optional(d.Element, path+".element")
+	if err != nil {
+		return nil, err
+	}
+	backgrounds := map[ButtonState]Drawer{}
+	for name, raw := range d.Backgrounds {
+		state, ok := buttonStates[name]
+		if !ok {
+			return nil, fmt.Errorf("ui %s: unknown button state '%s'", path, name)
+		}
+		bg, err := b.element(raw, path+".backgrounds."+name)
+		if err != nil {
+			return nil, err
+		}
+		backgrounds[state] = bg
+	}
+	button := &Button{
+		Backgrounds: backgrounds,
+		Element:     element,
+		Padding:     d.Padding,
+		Anchor:      anchor,
+		Fill:        d.Fill,
+		Wt:          d.Wt,
+		Disabled:    d.Disabled,
+	}
+	if d.OnClick != "" {
+		name, arg := splitBinding(d.OnClick)
+		fn, ok := b.bindings.Callbacks[name]
+		if !ok {
+			return nil, fmt.Errorf("ui %s: no callback '%s'", path, name)
+		}
+		button.OnClick = func() { fn(arg) }
+	}
+	return button, nil
+}
+
+func (b *defBuilder) text(d *ElementDef, path string) (WeightedDrawer, error) {
+	anchor, err := parseAnchor(d.Anchor)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	c, err := b.color(d.Color)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	align, err := parseTextAlign(d.TextAlign)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	if b.bindings.Face == nil {
+		return nil, fmt.Errorf("ui %s: no Face binding", path)
+	}
+	face, err := b.bindings.Face(d.Font, d.Size)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	t := &Text{
+		Anchor:      anchor,
+		Text:        d.Text,
+		Color:       c,
+		Face:        face,
+		Wt:          d.Wt,
+		Wrap:        d.Wrap,
+		Align:       align,
+		LineSpacing: d.LineSpacing,
+		Ellipsis:    d.Ellipsis,
+	}
+
+	binding := textBinding{text: t}
+	if d.TextSource != "" {
+		name, arg := splitBinding(d.TextSource)
+		fn, ok := b.bindings.Texts[name]
+		if !ok {
+			return nil, fmt.Errorf("ui %s: no text source '%s'", path, name)
+		}
+		binding.source = func() string { return fn(arg) }
+	}
+	if d.ColorSource != "" {
+		name, arg := splitBinding(d.ColorSource)
+		fn, ok := b.bindings.Colors[name]
+		if !ok {
+			return nil, fmt.Errorf("ui %s: no color source '%s'", path, name)
+		}
+		binding.color = func() color.Color { return fn(arg) }
+	}
+	if binding.source != nil || binding.color != nil {
+		b.tree.texts = append(b.tree.texts, binding)
+	}
+	return t, nil
+}
+
+func (b *defBuilder) image(d *ElementDef, path string) (WeightedDrawer, error) {
+	anchor, err := parseAnchor(d.Anchor)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	mode, err := parseScaleMode(d.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+
+	var img *ebiten.Image
+	if d.Img != "" {
+		if b.bindings.Image == nil {
+			return nil, fmt.Errorf("ui %s: no Image binding", path)
+		}
+		img, err = b.bindings.Image(d.Img)
+	} else {
+		img, err = b.solidImage(d.Color, d.Width, d.Height)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	return &Image{
+		Img:    img,
+		Mode:   mode,
+		Anchor: anchor,
+		Wt:     d.Wt,
+	}, nil
+}
+
+func (b *defBuilder) slider(d *ElementDef, path string) (WeightedDrawer, error) {
+	name, arg := splitBinding(d.Value)
+	value, ok := b.bindings.Numbers[name]
+	if !ok {
+		return nil, fmt.Errorf("ui %s: no number '%s'", path, name)
+	}
+	track, err := b.optional(d.Track, path+".track")
+	if err != nil {
+		return nil, err
+	}
+	thumb, err := b.optional(d.Thumb, path+".thumb")
+	if err != nil {
+		return nil, err
+	}
+	focusedThumb, err := b.optional(d.FocusedThumb, path+".focusedThumb")
+	if err != nil {
+		return nil, err
+	}
+	s := &Slider{
+		Min:          d.Min,
+		Max:          d.Max,
+		Step:         d.Step,
+		OnChange:     func(v float64) { value.Set(arg, v) },
+		Track:        track,
+		Thumb:        thumb,
+		FocusedThumb: focusedThumb,
+		ThumbSize:    geo.VecXY(d.ThumbSize[0], d.ThumbSize[1]),
+		Size:         geo.VecXY(d.Width, d.Height),
+		Wt:           d.Wt,
+		Disabled:     d.Disabled,
+	}
+	b.tree.values = append(b.tree.values, func() { s.Value = value.Get(arg) })
+	return s, nil
+}
+
+func (b *defBuilder) checkbox(d *ElementDef, path string) (WeightedDrawer, error) {
+	name, arg := splitBinding(d.Value)
+	value, ok := b.bindings.Bools[name]
+	if !ok {
+		return nil, fmt.Errorf("ui %s: no bool '%s'", path, name)
+	}
+	on, err := b.optional(d.On, path+".on")
+	if err != nil {
+		return nil, err
+	}
+	off, err := b.optional(d.Off, path+".off")
+	if err != nil {
+		return nil, err
+	}
+	highlight, err := b.optional(d.Highlight, path+".highlight")
+	if err != nil {
+		return nil, err
+	}
+	label, err := b.optional(d.Element, path+".element")
+	if err != nil {
+		return nil, err
+	}
+	c := &Checkbox{
+		OnChange:  func(v bool) { value.Set(arg, v) },
+		On:        on,
+		Off:       off,
+		Highlight: highlight,
+		BoxSize:   geo.VecXY(d.Width, d.Height),
+		Label:     label,
+		Gap:       d.Gap,
+		Wt:        d.Wt,
+		Disabled:  d.Disabled,
+	}
+	b.tree.values = append(b.tree.values, func() { c.Checked = value.Get(arg) })
+	return c, nil
+}
+
+func (b *defBuilder) cycle(d *ElementDef, path string) (WeightedDrawer, error) {
+	name, arg := splitBinding(d.Value)
+	value, ok := b.bindings.Strings[name]
+	if !ok {
+		return nil, fmt.Errorf("ui %s: no string '%s'", path, name)
+	}
+	list, ok := b.bindings.Lists[d.Items]
+	if !ok {
+		return nil, fmt.Errorf("ui %s: no list '%s'", path, d.Items)
+	}
+	c, err := b.color(d.Color)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	if b.bindings.Face == nil {
+		return nil, fmt.Errorf("ui %s: no Face binding", path)
+	}
+	face, err := b.bindings.Face(d.Font, d.Size)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	highlight, err := b.optional(d.Highlight, path+".highlight")
+	if err != nil {
+		return nil, err
+	}
+	cycle := &Cycle{
+		Options:   list(),
+		Face:      face,
+		Color:     c,
+		Highlight: highlight,
+		Gap:       d.Gap,
+		Wt:        d.Wt,
+		Disabled:  d.Disabled,
+	}
+	cycle.OnChange = func(i int) { value.Set(arg, cycle.Options[i]) }
+	b.tree.values = append(b.tree.values, func() {
+		selected := value.Get(arg)
+		for i, option := range cycle.Options {
+			if option == selected {
+				cycle.Index = i
+				return
+			}
+		}
+	})
+	return cycle, nil
+}
+
+func (b *defBuilder) textInput(d *ElementDef, path string) (WeightedDrawer, error) {
+	name, arg := splitBinding(d.Value)
+	value, ok := b.bindings.Strings[name]
+	if !ok {
+		return nil, fmt.Errorf("ui %s: no string '%s'", path, name)
+	}
+	c, err := b.color(d.Color)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	if b.bindings.Face == nil {
+		return nil, fmt.Errorf("ui %s: no Face binding", path)
+	}
+	face, err := b.bindings.Face(d.Font, d.Size)
+	if err != nil {
+		return nil, fmt.Errorf("ui %s: %v", path, err)
+	}
+	backgrounds := map[string]Drawer{}
+	for state, raw := range d.Backgrounds {
+		if state != "idle" && state != "focused" && state != "editing" {
+			return nil, fmt.Errorf("ui %s: unknown text input state '%s'", path, state)
+		}
+		bg, err := b.element(raw, path+".backgrounds."+state)
+		if err != nil {
+			return nil, err
+		}
+		backgrounds[state] = bg
+	}
+	t := &TextInput{
+		MaxLen:            d.MaxLen,
+		OnChange:          func(text string) { value.Set(arg, text) },
+		Face:              face,
+		Color:             c,
+		Background:        backgrounds["idle"],
+		FocusedBackground: backgrounds["focused"],
+		EditingBackground: backgrounds["editing"],
+		Padding:           d.Padding,
+		Width:             d.Width,
+		Wt:                d.Wt,
+		Disabled:          d.Disabled,
+	}
+	if d.OnSubmit != "" {
+		name, arg := splitBinding(d.OnSubmit)
+		fn, ok := b.bindings.Callbacks[name]
+		if !ok {
+			return nil, fmt.Errorf("ui %s: no callback '%s'", path, name)
+		}
+		t.OnSubmit = func(string) { fn(arg) }
+	}
+	b.tree.values = append(b.tree.values, func() {
+		// Leave the text alone while it's being typed.
+		if !t.Editing {
+			t.Text = value.Get(arg)
+		}
+	})
+	return t, nil
+}
+
+// solidImage returns an image of a single color. It's 1x1 unless a size is given, which is
+// only needed for it to have a preferred size.
+func (b *defBuilder) solidImage(c string, width, height float64) (*ebiten.Image, error) {
+	parsed, err := b.color(c)
+	if err != nil {
+		return nil, err
+	}
+	w, h := int(width), int(height)
+	if w <= 0 {
+		w = 1
+	}
+	if h <= 0 {
+		h = 1
+	}
+	if w == 1 && h == 1 {
+		if img, ok := b.solid[parsed]; ok {
+			return img, nil
+		}
+	}
+	img, err := ebiten.NewImage(w, h, ebiten.FilterNearest)
+	if err != nil {
+		return nil, err
+	}
+	img.Fill(parsed)
+	if w == 1 && h == 1 {
+		b.solid[parsed] = img
+	}
+	return img, nil
+}
+
+// color parses a color, which may refer to one of the Theme's colors.
+func (b *defBuilder) color(s string) (color.NRGBA, error) {
+	if strings.HasPrefix(s, "@") {
+		return b.bindings.Theme.color(s[1:])
+	}
+	return parseColor(s)
+}
+
+var buttonStates = map[string]ButtonState{
+	"idle":     ButtonIdle,
+	"hover":    ButtonHover,
+	"pressed":  ButtonPressed,
+	"disabled": ButtonDisabled,
+	"focused":  ButtonFocused,
+}
+
+// splitBinding splits a binding name from its argument.
+func splitBinding(s string) (name, arg string) {
+	if i := strings.Index(s, ":"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+func parseAnchor(raw json.RawMessage) (Anchor, error) {
+	if len(raw) == 0 {
+		return Anchor{}, nil
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		switch name {
+		case "center":
+			return AnchorCenter, nil
+		case "left":
+			return AnchorLeft, nil
+		case "right":
+			return AnchorRight, nil
+		}
+		return Anchor{}, fmt.Errorf("unknown anchor '%s'", name)
+	}
+	var a struct {
+		Src, Dst, Offset [2]float64
+	}
+	if err := json.Unmarshal(raw, &a); err != nil {
+		return Anchor{}, fmt.Errorf("anchor: %v", err)
+	}
+	return Anchor{
+		Src:    geo.VecXY(a.Src[0], a.Src[1]),
+		Dst:    geo.VecXY(a.Dst[0], a.Dst[1]),
+		Offset: geo.VecXY(a.Offset[0], a.Offset[1]),
+	}, nil
+}
+
+func parseColor(s string) (color.NRGBA, error) {
+	switch s {
+	case "", "black":
+		return color.NRGBA{0, 0, 0, 0xff}, nil
+	case "white":
+		return color.NRGBA{0xff, 0xff, 0xff, 0xff}, nil
+	case "transparent":
+		return color.NRGBA{}, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil || !strings.HasPrefix(s, "#") {
+		return color.NRGBA{}, fmt.Errorf("invalid color '%s'", s)
+	}
+	return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+func parseCrossAlign(s string) (CrossAlign, error) {
+	switch s {
+	case "", "stretch":
+		return CrossStretch, nil
+	case "start":
+		return CrossStart, nil
+	case "center":
+		return CrossCenter, nil
+	case "end":
+		return CrossEnd, nil
+	}
+	return 0, fmt.Errorf("unknown align '%s'", s)
+}
+
+func parseTextAlign(s string) (TextAlign, error) {
+	switch s {
+	case "", "left":
+		return AlignLeft, nil
+	case "center":
+		return AlignCenter, nil
+	case "right":
+		return AlignRight, nil
+	}
+	return 0, fmt.Errorf("unknown text align '%s'", s)
+}
+
+func parseScaleMode(s string) (ScaleMode, error) {
+	switch s {
+	case "", "none":
+		return ScaleNone, nil
+	case "stretch":
+		return ScaleStretch, nil
+	case "fit":
+		return ScaleFit, nil
+	case "fill":
+		return ScaleFill, nil
+	}
+	return 0, fmt.Errorf("unknown scale mode '%s'", s)
+}
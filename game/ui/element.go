@@ -2,6 +2,8 @@ package ui
 
 import (
 	"image/color"
+	"math"
+	"strings"
 
 	"golang.org/x/image/font"
 
@@ -10,25 +12,180 @@ import (
 	"github.com/hajimehoshi/ebiten/text"
 )
 
-// Text is an element that contains text.
+// ellipsis replaces the end of text that doesn't fit when Text.Ellipsis is set.
+const ellipsis = "..."
+
+// TextAlign is the horizontal alignment of each line within a multi-line Text.
+type TextAlign int
+
+const (
+	// AlignLeft lines up the left edges of the lines.
+	AlignLeft TextAlign = iota
+	// AlignCenter centers each line.
+	AlignCenter
+	// AlignRight lines up the right edges of the lines.
+	AlignRight
+)
+
+// Text is an element that contains text. The text is split into lines at newlines, and
+// also between words if Wrap is set. The lines are aligned with each other according to
+// Align and the whole block of lines is positioned with Anchor.
 type Text struct {
 	Anchor Anchor
 	Text   string
 	Color  color.Color
 	Face   font.Face
 	Wt     float64
+	// Wrap breaks lines between words so that they fit within the width of the bounds given
+	// to Draw. A word that is wider than the bounds on its own still gets its own line.
+	Wrap  bool
+	Align TextAlign
+	// LineSpacing scales the distance between lines, where 1 is the Face's ascent plus
+	// descent. 0 is treated as 1.
+	LineSpacing float64
+	// Ellipsis cuts off lines that are too wide for the bounds, and lines that don't fit in
+	// its height, with "..." to show that there is more.
+	Ellipsis bool
 }
 
 // Draw draws the Text to the image within bounds.
 func (t *Text) Draw(dst *ebiten.Image, bounds geo.Rect) {
-	b, _ := font.BoundString(t.Face, t.Text)
-	width := float64((b.Max.X - b.Min.X).Ceil())
-	height := float64((b.Max.Y - b.Min.Y).Ceil())
-	offset := (t.Face.Metrics().Height - t.Face.Metrics().Descent).Floor() // - 1
-	textBounds := geo.RectWH(width, height)
-
+	lines := t.lines(bounds.W)
+	if t.Ellipsis {
+		lines = t.truncate(lines, bounds)
+	}
+	size := t.blockSize(lines)
+	textBounds := geo.RectWH(size.XY())
 	textBounds.SetTopLeft(t.Anchor.TopLeft(textBounds, bounds).XY())
-	text.Draw(dst, t.Text, t.Face, int(textBounds.X), int(textBounds.Y)+offset, t.Color)
+
+	ascent := t.Face.Metrics().Ascent.Ceil()
+	for i, line := range lines {
+		x := textBounds.X
+		switch t.Align {
+		case AlignCenter:
+			x += (size.X - t.width(line)) / 2
+		case AlignRight:
+			x += size.X - t.width(line)
+		}
+		y := textBounds.Y + float64(i)*t.lineAdvance()
+		text.Draw(dst, line, t.Face, int(x), int(y)+ascent, t.Color)
+	}
+}
+
+// PreferredSize returns the size of the Text without wrapping. The width is how far the
+// widest line advances and the height of each line is the Face's ascent plus descent, so it
+// doesn't depend on which glyphs are used and lines of text in the same Face line up.
+func (t *Text) PreferredSize() geo.Vec {
+	return t.WrappedSize(0)
+}
+
+// MinSize returns the smallest size the Text can be drawn in without overlapping anything.
+// That is one line of "..." if Ellipsis is set, and the width of the longest word if Wrap
+// is set.
+func (t *Text) MinSize() geo.Vec {
+	switch {
+	case t.Ellipsis:
+		return geo.VecXY(t.width(ellipsis), t.lineHeight())
+	case t.Wrap:
+		widest := 0.0
+		for _, word := range strings.Fields(t.Text) {
+			widest = math.Max(widest, t.width(word))
+		}
+		return t.WrappedSize(widest)
+	}
+	return t.PreferredSize()
+}
+
+// WrappedSize returns the size that the Text would take up if drawn within maxWidth.
+// maxWidth only matters if Wrap is set, and 0 means there is no limit.
+func (t *Text) WrappedSize(maxWidth float64) geo.Vec {
+	return t.blockSize(t.lines(maxWidth))
+}
+
+// lines splits the text into lines that fit within width if wrapping.
+func (t *Text) lines(width float64) []string {
+	paragraphs := strings.Split(t.Text, "\n")
+	if !t.Wrap || width <= 0 {
+		return paragraphs
+	}
+	var lines []string
+	for _, p := range paragraphs {
+		line := ""
+		for _, word := range strings.Fields(p) {
+			if line == "" {
+				line = word
+				continue
+			}
+			if t.width(line+" "+word) <= width {
+				line += " " + word
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// truncate removes the lines that don't fit in bounds and shortens the ones that are too
+// wide, marking where text was removed with an ellipsis.
+func (t *Text) truncate(lines []string, bounds geo.Rect) []string {
+	maxLines := len(lines)
+	if bounds.H < t.lineHeight() {
+		maxLines = 1
+	} else if n := int(math.Floor((bounds.H-t.lineHeight())/t.lineAdvance())) + 1; n < maxLines {
+		maxLines = n
+	}
+	truncated := make([]string, maxLines)
+	for i := range truncated {
+		line := lines[i]
+		cut := i == maxLines-1 && maxLines < len(lines)
+		if cut || t.width(line) > bounds.W {
+			line = t.shorten(line, bounds.W)
+		}
+		truncated[i] = line
+	}
+	return truncated
+}
+
+// shorten removes characters from the end of line until it fits within width with an
+// ellipsis added.
+func (t *Text) shorten(line string, width float64) string {
+	runes := []rune(line)
+	for len(runes) > 0 && t.width(string(runes)+ellipsis) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + ellipsis
+}
+
+// blockSize returns the size of the lines when drawn together.
+func (t *Text) blockSize(lines []string) geo.Vec {
+	width := 0.0
+	for _, line := range lines {
+		width = math.Max(width, t.width(line))
+	}
+	height := t.lineHeight() + float64(len(lines)-1)*t.lineAdvance()
+	return geo.VecXY(width, height)
+}
+
+func (t *Text) width(s string) float64 {
+	return float64(font.MeasureString(t.Face, s).Ceil())
+}
+
+// lineHeight is the height of a single line.
+func (t *Text) lineHeight() float64 {
+	m := t.Face.Metrics()
+	return float64((m.Ascent + m.Descent).Ceil())
+}
+
+// lineAdvance is the distance from the top of one line to the top of the next.
+func (t *Text) lineAdvance() float64 {
+	spacing := t.LineSpacing
+	if spacing == 0 {
+		spacing = 1
+	}
+	return math.Ceil(t.lineHeight() * spacing)
 }
 
 // Weight returns the relative weight for allocating space within a container.
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/Bredgren/geo"
+)
+
+// Direction is a direction that focus can move in.
+type Direction int
+
+// Directions for Focus.Move.
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+func (d Direction) vec() geo.Vec {
+	switch d {
+	case Up:
+		return geo.VecXY(0, -1)
+	case Down:
+		return geo.VecXY(0, 1)
+	case Left:
+		return geo.VecXY(-1, 0)
+	}
+	return geo.VecXY(1, 0)
+}
+
+// Focusable is an element that can have focus within a Focus group.
+type Focusable interface {
+	// SetFocus gives or takes away focus.
+	SetFocus(focused bool)
+	// Rect returns where the element was last drawn. Navigation between elements is based
+	// on these.
+	Rect() geo.Rect
+	// Activate does whatever the element does when it is clicked.
+	Activate()
+	// CanFocus returns false if the element should be skipped, e.g. because it's disabled.
+	CanFocus() bool
+}
+
+// Focus keeps track of which one of a group of elements has focus. Focus moves to the
+// nearest element in a direction, based on where they were last drawn, so the order of
+// Elements doesn't matter.
+type Focus struct {
+	Elements []Focusable
+	current  Focusable
+}
+
+// Current returns the element with focus, or nil if there isn't one.
+func (f *Focus) Current() Focusable {
+	return f.current
+}
+
+// Set moves focus to e, which may be nil to clear it.
+func (f *Focus) Set(e Focusable) {
+	if f.current == e {
+		return
+	}
+	if f.current != nil {
+		f.current.SetFocus(false)
+	}
+	f.current = e
+	if e != nil {
+		e.SetFocus(true)
+	}
+}
+
+// Move moves focus to the nearest element in the given direction from the current one and
+// returns true if focus changed. If nothing has focus then the top left element gets it.
+// Elements that haven't been drawn yet or can't be focused are skipped.
+func (f *Focus) Move(dir Direction) bool {
+	if f.current == nil {
+		first := f.topLeft()
+		f.Set(first)
+		return first != nil
+	}
+
+	d := dir.vec()
+	from := center(f.current.Rect())
+	var best Focusable
+	bestScore := math.Inf(1)
+	for _, e := range f.Elements {
+		if e == f.current || !e.CanFocus() || e.Rect().W == 0 || e.Rect().H == 0 {
+			continue
+		}
+		offset := center(e.Rect()).Minus(from)
+		along := offset.X*d.X + offset.Y*d.Y
+		if along <= 0 {
+			continue
+		}
+		// Prefer elements that are lined up with the current one over closer ones that are
+		// off to the side.
+		across := math.Abs(offset.X*d.Y - offset.Y*d.X)
+		if score := along + 2*across; score < bestScore {
+			best, bestScore = e, score
+		}
+	}
+	if best == nil {
+		return false
+	}
+	f.Set(best)
+	return true
+}
+
+// Activate activates the element with focus and returns true if there is one.
+func (f *Focus) Activate() bool {
+	if f.current == nil {
+		return false
+	}
+	f.current.Activate()
+	return true
+}
+
+func (f *Focus) topLeft() Focusable {
+	var best Focusable
+	for _, e := range f.Elements {
+		r := e.Rect()
+		if !e.CanFocus() || r.W == 0 || r.H == 0 {
+			continue
+		}
+		if best == nil || r.Y < best.Rect().Y || (r.Y == best.Rect().Y && r.X < best.Rect().X) {
+			best = e
+		}
+	}
+	return best
+}
+
+func center(r geo.Rect) geo.Vec {
+	return geo.VecXY(r.X+r.W/2, r.Y+r.H/2)
+}
@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/Bredgren/game1/game/sprite"
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// ScaleMode is how an Image fits itself into the bounds it's drawn in.
+type ScaleMode int
+
+const (
+	// ScaleNone draws the image at its own size, positioned by the Image's Anchor.
+	ScaleNone ScaleMode = iota
+	// ScaleStretch stretches the image to fill the bounds.
+	ScaleStretch
+	// ScaleFit scales the image as large as it can be within the bounds while keeping its
+	// aspect ratio, positioned by the Image's Anchor.
+	ScaleFit
+	// ScaleFill scales the image to cover the bounds while keeping its aspect ratio. The
+	// parts that don't fit are cut off, the Image's Anchor chooses which part is kept.
+	ScaleFill
+)
+
+// Image is an element that draws an image, or part of one. It has no size and draws
+// nothing while Img is nil.
+type Image struct {
+	Img *ebiten.Image
+	// Src is the part of Img to draw. The whole image is drawn if it's empty.
+	Src    image.Rectangle
+	Mode   ScaleMode
+	Anchor Anchor
+	Wt     float64
+}
+
+// ImageFromDesc returns an Image of the given frame of a sprite. Only the frame's opaque
+// part is drawn, see sprite.FrameDesc.
+func ImageFromDesc(d *sprite.Desc, frame int) (*Image, error) {
+	if frame < 0 || frame >= len(d.Frames) {
+		return nil, fmt.Errorf("sprite '%s': no frame %d", d.Name, frame)
+	}
+	f := d.Frames[frame]
+	if f.Img == nil {
+		return nil, fmt.Errorf("sprite '%s': frame %d is empty", d.Name, frame)
+	}
+	return &Image{Img: f.Img, Src: f.Src}, nil
+}
+
+// Draw draws the Image to dst within bounds.
+func (i *Image) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	src := srcRect(i.Img, i.Src)
+	size := geo.VecXYi(src.Dx(), src.Dy())
+	if size.X == 0 || size.Y == 0 {
+		return
+	}
+
+	scale := geo.VecXY(1, 1)
+	switch i.Mode {
+	case ScaleStretch:
+		scale = geo.VecXY(bounds.W/size.X, bounds.H/size.Y)
+	case ScaleFit:
+		s := math.Min(bounds.W/size.X, bounds.H/size.Y)
+		scale = geo.VecXY(s, s)
+	case ScaleFill:
+		s := math.Max(bounds.W/size.X, bounds.H/size.Y)
+		scale = geo.VecXY(s, s)
+		// Only draw the part of the image that is within bounds.
+		visible := geo.VecXY(bounds.W/s, bounds.H/s)
+		x := src.Min.X + int((size.X-visible.X)*i.Anchor.Src.X)
+		y := src.Min.Y + int((size.Y-visible.Y)*i.Anchor.Src.Y)
+		src = image.Rect(x, y, x+int(visible.X), y+int(visible.Y))
+		size = visible
+	}
+
+	r := geo.RectWH(size.X*scale.X, size.Y*scale.Y)
+	r.SetTopLeft(i.Anchor.TopLeft(r, bounds).XY())
+	drawRegion(dst, i.Img, src, r)
+}
+
+// MinSize returns the size of the image if it isn't scaled, otherwise 0.
+func (i *Image) MinSize() geo.Vec {
+	if i.Mode == ScaleNone {
+		return i.PreferredSize()
+	}
+	return geo.Vec0
+}
+
+// PreferredSize returns the size of the image.
+func (i *Image) PreferredSize() geo.Vec {
+	src := srcRect(i.Img, i.Src)
+	return geo.VecXYi(src.Dx(), src.Dy())
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (i *Image) Weight() float64 {
+	return i.Wt
+}
+
+// NineSlice is an element that stretches an image to any size without stretching its
+// borders. The corners are drawn as they are, the edges are stretched along their length
+// and the center is stretched in both directions.
+type NineSlice struct {
+	Img *ebiten.Image
+	// Src is the part of Img to use. The whole image is used if it's empty.
+	Src image.Rectangle
+	// Border is the size of each edge of Src in pixels.
+	Border Insets
+	Wt     float64
+}
+
+// NineSliceFromDesc returns a NineSlice made from the first frame of a sprite. The frame
+// must have a Rect named "center" which is the part of the image that gets stretched in
+// both directions, the rest of the image is the border.
+func NineSliceFromDesc(d *sprite.Desc) (*NineSlice, error) {
+	img, err := ImageFromDesc(d, 0)
+	if err != nil {
+		return nil, err
+	}
+	centers := d.Frames[0].Rects["center"]
+	if len(centers) == 0 {
+		return nil, fmt.Errorf("sprite '%s': no center rect", d.Name)
+	}
+	// Rects are relative to the whole frame while Src is only the opaque part.
+	c := centers[0]
+	offset := d.Frames[0].Offset
+	left := c.X - offset.X
+	top := c.Y - offset.Y
+	return &NineSlice{
+		Img: img.Img,
+		Src: img.Src,
+		Border: Insets{
+			Left:   left,
+			Top:    top,
+			Right:  float64(img.Src.Dx()) - left - c.W,
+			Bottom: float64(img.Src.Dy()) - top - c.H,
+		},
+	}, nil
+}
+
+// Draw draws the NineSlice to dst filling bounds. Nothing is drawn if Img is nil.
+func (n *NineSlice) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	if n.Img == nil {
+		return
+	}
+	src := srcRect(n.Img, n.Src)
+	b := n.Border
+	// The x and y positions of each column and row edge in the source and destination.
+	srcXs := []int{src.Min.X, src.Min.X + int(b.Left), src.Max.X - int(b.Right), src.Max.X}
+	srcYs := []int{src.Min.Y, src.Min.Y + int(b.Top), src.Max.Y - int(b.Bottom), src.Max.Y}
+	dstXs := []float64{bounds.X, bounds.X + b.Left, bounds.X + bounds.W - b.Right, bounds.X + bounds.W}
+	dstYs := []float64{bounds.Y, bounds.Y + b.Top, bounds.Y + bounds.H - b.Bottom, bounds.Y + bounds.H}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			s := image.Rect(srcXs[col], srcYs[row], srcXs[col+1], srcYs[row+1])
+			d := geo.RectXYWH(dstXs[col], dstYs[row], dstXs[col+1]-dstXs[col], dstYs[row+1]-dstYs[row])
+			drawRegion(dst, n.Img, s, d)
+		}
+	}
+}
+
+// MinSize returns the size of the borders.
+func (n *NineSlice) MinSize() geo.Vec {
+	return n.Border.Grow(geo.Vec0)
+}
+
+// PreferredSize returns the size of the image.
+func (n *NineSlice) PreferredSize() geo.Vec {
+	src := srcRect(n.Img, n.Src)
+	return geo.VecXYi(src.Dx(), src.Dy())
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (n *NineSlice) Weight() float64 {
+	return n.Wt
+}
+
+// srcRect returns src, or the bounds of img if src is empty. It's empty if img is nil.
+func srcRect(img *ebiten.Image, src image.Rectangle) image.Rectangle {
+	if img == nil {
+		return image.Rectangle{}
+	}
+	if src.Empty() {
+		w, h := img.Size()
+		return image.Rect(0, 0, w, h)
+	}
+	return src
+}
+
+// drawRegion draws the src part of img scaled to fill r.
+func drawRegion(dst, img *ebiten.Image, src image.Rectangle, r geo.Rect) {
+	if src.Empty() || r.W <= 0 || r.H <= 0 {
+		return
+	}
+	opts := ebiten.DrawImageOptions{SourceRect: &src}
+	opts.GeoM.Scale(r.W/float64(src.Dx()), r.H/float64(src.Dy()))
+	opts.GeoM.Translate(r.X, r.Y)
+	dst.DrawImage(img, &opts)
+}
+
+// fitBuffer returns buf, or a new image if buf is nil or a different size than dst, for
+// drawing elements off screen in the same coordinates as dst.
+func fitBuffer(buf, dst *ebiten.Image) (*ebiten.Image, error) {
+	w, h := dst.Size()
+	if buf != nil {
+		if bw, bh := buf.Size(); bw == w && bh == h {
+			return buf, nil
+		}
+		buf.Dispose()
+	}
+	return ebiten.NewImage(w, h, ebiten.FilterNearest)
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/Bredgren/geo"
+)
+
+// Sizer is an element that knows how much space it needs. Elements that aren't Sizers are
+// treated as having no size.
+type Sizer interface {
+	// MinSize returns the smallest size that the element can be drawn in.
+	MinSize() geo.Vec
+	// PreferredSize returns the size that the element would like to be drawn in.
+	PreferredSize() geo.Vec
+}
+
+// Insets is an amount of space inside each edge of a rectangle.
+type Insets struct {
+	Left, Top, Right, Bottom float64
+}
+
+// InsetsAll returns Insets that are the same on every side.
+func InsetsAll(n float64) Insets {
+	return Insets{Left: n, Top: n, Right: n, Bottom: n}
+}
+
+// Shrink returns r with the insets removed. The size won't go below 0.
+func (in Insets) Shrink(r geo.Rect) geo.Rect {
+	return geo.RectXYWH(r.X+in.Left, r.Y+in.Top,
+		math.Max(0, r.W-in.Left-in.Right), math.Max(0, r.H-in.Top-in.Bottom))
+}
+
+// Grow returns size with the insets added.
+func (in Insets) Grow(size geo.Vec) geo.Vec {
+	return geo.VecXY(size.X+in.Left+in.Right, size.Y+in.Top+in.Bottom)
+}
+
+// CrossAlign is how a container positions its elements across the direction it orders
+// them in, e.g. horizontally within a VerticalContainer.
+type CrossAlign int
+
+const (
+	// CrossStretch makes elements as big as the container.
+	CrossStretch CrossAlign = iota
+	// CrossStart puts elements at their preferred size at the top or left.
+	CrossStart
+	// CrossCenter centers elements at their preferred size.
+	CrossCenter
+	// CrossEnd puts elements at their preferred size at the bottom or right.
+	CrossEnd
+)
+
+// minSize returns e's minimum size if it's a Sizer.
+func minSize(e Drawer) geo.Vec {
+	if s, ok := e.(Sizer); ok {
+		return s.MinSize()
+	}
+	return geo.Vec0
+}
+
+// preferredSize returns e's preferred size if it's a Sizer.
+func preferredSize(e Drawer) geo.Vec {
+	if s, ok := e.(Sizer); ok {
+		return s.PreferredSize()
+	}
+	return geo.Vec0
+}
+
+// line lays out elements in a row or column. Sizes are handled as (main, cross) vectors,
+// where main is along the direction the elements are ordered in.
+type line struct {
+	elements []WeightedDrawer
+	padding  Insets
+	gap      float64
+	align    CrossAlign
+	vertical bool
+}
+
+// orient swaps v's components if the line is vertical, converting between (x, y) and
+// (main, cross).
+func (l line) orient(v geo.Vec) geo.Vec {
+	if l.vertical {
+		return geo.VecXY(v.Y, v.X)
+	}
+	return v
+}
+
+// size adds up the sizes of the elements given by fn along the main axis and takes the
+// largest across it.
+func (l line) size(fn func(e Drawer) geo.Vec) geo.Vec {
+	var total geo.Vec
+	for _, e := range l.elements {
+		s := l.orient(fn(e))
+		total.X += s.X
+		total.Y = math.Max(total.Y, s.Y)
+	}
+	if len(l.elements) > 1 {
+		total.X += l.gap * float64(len(l.elements)-1)
+	}
+	return l.padding.Grow(l.orient(total))
+}
+
+// layout returns the bounds of each element within bounds. Elements with no Weight get
+// their preferred size along the main axis and the rest of the space is shared between
+// weighted elements according to their Weights. No element is made smaller than its
+// MinSize, even if that means the elements don't fit in bounds.
+func (l line) layout(bounds geo.Rect) []geo.Rect {
+	inner := l.padding.Shrink(bounds)
+	avail := l.orient(geo.VecXY(inner.W, inner.H))
+
+	prefs := make([]geo.Vec, len(l.elements))
+	mins := make([]geo.Vec, len(l.elements))
+	for i, e := range l.elements {
+		prefs[i] = l.orient(preferredSize(e))
+		mins[i] = l.orient(minSize(e))
+	}
+	mains := l.mainSizes(avail.X, prefs, mins)
+
+	rects := make([]geo.Rect, len(l.elements))
+	pos := 0.0
+	for i := range l.elements {
+		size := geo.VecXY(mains[i], math.Max(avail.Y, mins[i].Y))
+		cross := 0.0
+		if l.align != CrossStretch {
+			size.Y = math.Max(math.Min(prefs[i].Y, avail.Y), mins[i].Y)
+			switch l.align {
+			case CrossCenter:
+				cross = (avail.Y - size.Y) / 2
+			case CrossEnd:
+				cross = avail.Y - size.Y
+			}
+		}
+		offset := l.orient(geo.VecXY(pos, cross))
+		size = l.orient(size)
+		rects[i] = geo.RectXYWH(inner.X+offset.X, inner.Y+offset.Y, size.X, size.Y)
+		pos += l.orient(size).X + l.gap
+	}
+	return rects
+}
+
+// mainSizes returns the size of each element along the main axis when there is avail
+// space, given the elements' oriented preferred and minimum sizes. Weighted elements whose
+// share would be below their minimum get their minimum instead, and the rest of the space
+// is shared again between the others.
+func (l line) mainSizes(avail float64, prefs, mins []geo.Vec) []float64 {
+	sizes := make([]float64, len(l.elements))
+	remaining := avail
+	if len(l.elements) > 1 {
+		remaining -= l.gap * float64(len(l.elements)-1)
+	}
+	for i, e := range l.elements {
+		if e.Weight() <= 0 {
+			sizes[i] = math.Max(prefs[i].X, mins[i].X)
+			remaining -= sizes[i]
+		}
+	}
+
+	atMin := make([]bool, len(l.elements))
+	for {
+		share, totalWeight := remaining, 0.0
+		for i, e := range l.elements {
+			switch {
+			case e.Weight() <= 0:
+			case atMin[i]:
+				share -= mins[i].X
+			default:
+				totalWeight += e.Weight()
+			}
+		}
+		share = math.Max(0, share)
+		done := true
+		for i, e := range l.elements {
+			if e.Weight() <= 0 || atMin[i] {
+				continue
+			}
+			sizes[i] = e.Weight() / totalWeight * share
+			if sizes[i] < mins[i].X {
+				atMin[i] = true
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+	for i := range l.elements {
+		if atMin[i] {
+			sizes[i] = mins[i].X
+		}
+	}
+	return sizes
+}
@@ -0,0 +1,483 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"github.com/Bredgren/game1/game/keymap"
+	"github.com/Bredgren/game1/game/tween"
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Actions handled by the KeyMap returned from Root.KeyMap. Bind buttons and axes to them
+// to control the UI.
+const (
+	ActionClick   keymap.Action = "ui click"
+	ActionUp      keymap.Action = "ui up"
+	ActionDown    keymap.Action = "ui down"
+	ActionLeft    keymap.Action = "ui left"
+	ActionRight   keymap.Action = "ui right"
+	ActionConfirm keymap.Action = "ui confirm"
+	// ActionNavX and ActionNavY are axis actions that move focus when pushed past
+	// NavAxisThreshold.
+	ActionNavX keymap.Action = "ui left/right"
+	ActionNavY keymap.Action = "ui up/down"
+	// ActionScrollY is an axis action that scrolls what has focus, or is under the mouse,
+	// while pushed past ScrollAxisThreshold.
+	ActionScrollY keymap.Action = "ui scroll"
+)
+
+const (
+	// NavAxisThreshold is how far an axis must be pushed to move focus.
+	NavAxisThreshold = 0.5
+	// ScrollAxisThreshold is how far an axis must be pushed to scroll.
+	ScrollAxisThreshold = 0.2
+	// AxisScrollSpeed is how many pixels per frame an axis scrolls when pushed all the way.
+	AxisScrollSpeed = 6
+	// WheelScrollStep is how many pixels each step of the mouse wheel scrolls.
+	WheelScrollStep = 20
+)
+
+// EventType is the kind of an Event.
+type EventType int
+
+const (
+	// EventEnter is sent when the mouse moves over an element.
+	EventEnter EventType = iota
+	// EventLeave is sent when the mouse moves off of an element.
+	EventLeave
+	// EventPress is sent when the click button is pressed over an element.
+	EventPress
+	// EventRelease is sent to the element that was pressed when the button is released.
+	EventRelease
+	// EventClick is sent when the button is released over the element that was pressed.
+	EventClick
+	// EventDrag is sent to the element that was pressed when the mouse moves before the
+	// button is released.
+	EventDrag
+)
+
+// Event is a mouse event dispatched by a Root.
+type Event struct {
+	Type EventType
+	// Pos is the position of the mouse.
+	Pos geo.Vec
+}
+
+// EventHandler is an element that receives events from a Root.
+type EventHandler interface {
+	// Rect returns where the element was last drawn, used for hit testing.
+	Rect() geo.Rect
+	// HandleEvent handles the event and returns true if it shouldn't be passed on to the
+	// element's ancestors. Enter and Leave events are never passed on.
+	HandleEvent(e Event) (handled bool)
+}
+
+// DirectionHandler is a Focusable that can use navigation itself while it has focus,
+// e.g. to change a value, instead of focus moving to another element.
+type DirectionHandler interface {
+	HandleDirection(dir Direction) (handled bool)
+}
+
+// FocusUpdater is a Focusable that needs to be updated every frame while it has focus.
+type FocusUpdater interface {
+	UpdateFocused()
+}
+
+// Capturer is a Focusable that can take all keyboard input while it has focus, e.g. to
+// type text.
+type Capturer interface {
+	Capturing() bool
+}
+
+// Scroller is an element that scrolls its contents, see ScrollContainer.
+type Scroller interface {
+	// Scroll moves the contents by delta and returns false if they couldn't move.
+	Scroll(delta float64) bool
+	// ScrollTo makes r visible.
+	ScrollTo(r geo.Rect)
+}
+
+// Parent is an element that contains other elements.
+type Parent interface {
+	Children() []WeightedDrawer
+}
+
+// Root owns a tree of elements and dispatches input to them. Hit testing uses the
+// rectangles that elements were last drawn at, so later siblings are on top of earlier
+// ones and children are on top of their parents. Every Focusable element in the tree is
+// part of the Root's focus group, and focus follows the mouse.
+//
+// If Element is a Sizer then it is drawn at its preferred size, limited to Bounds, and
+// positioned within Bounds by Anchor. Otherwise, or if Fill is set, it fills Bounds.
+type Root struct {
+	Element Drawer
+	Bounds  geo.Rect
+	Anchor  Anchor
+	Fill    bool
+	// Hidden roots aren't drawn and ignore input. Setting it directly skips the transitions
+	// of Show and Hide.
+	Hidden bool
+	// Modal roots keep navigation and confirm actions from reaching later keymap layers
+	// while they're shown. Clicks only stop when they hit an element in the Root.
+	Modal bool
+	// OnShow and OnHide are called by Show and Hide, e.g. to add enter and exit transitions
+	// to Tweens. Tweens is cleared before either is called, so a transition that interrupts
+	// another should start from where the other got to. OnShow can tell that it interrupted
+	// Hide with Hiding.
+	OnShow func()
+	OnHide func()
+	// Tweens is updated by Update. Hide waits for it to finish before hiding the Root.
+	Tweens tween.Group
+
+	rect      geo.Rect
+	focus     Focus
+	hover     EventHandler
+	pressed   EventHandler
+	lastMouse geo.Vec
+	lastFocus Focusable
+	clickDown bool
+	navDown   map[keymap.Action]bool
+	hiding    bool
+}
+
+// Focus returns the Root's focus group.
+func (r *Root) Focus() *Focus {
+	return &r.focus
+}
+
+// Show unhides the Root and calls OnShow. It does nothing if the Root is already shown,
+// unless it's being hidden.
+func (r *Root) Show() {
+	if !r.Hidden && !r.hiding {
+		return
+	}
+	r.Hidden = false
+	r.Tweens.Clear()
+	if r.OnShow != nil {
+		r.OnShow()
+	}
+	r.hiding = false
+}
+
+// Hide calls OnHide and then hides the Root once Tweens are finished. The Root ignores
+// input in the meantime.
+func (r *Root) Hide() {
+	if r.Hidden || r.hiding {
+		return
+	}
+	r.hiding = true
+	r.setHover(nil)
+	r.pressed = nil
+	r.Tweens.Clear()
+	if r.OnHide != nil {
+		r.OnHide()
+	}
+	if !r.Tweens.Active() {
+		r.finishHiding()
+	}
+}
+
+// Hiding returns true while the Root is waiting for Tweens to finish before hiding.
+func (r *Root) Hiding() bool {
+	return r.hiding
+}
+
+func (r *Root) finishHiding() {
+	r.hiding = false
+	r.Hidden = true
+}
+
+// Update advances Tweens by dt, and updates which element the mouse is over and the
+// elements in the focus group. It should be called once per frame.
+func (r *Root) Update(dt time.Duration) {
+	r.Tweens.Update(dt)
+	if r.hiding && !r.Tweens.Active() {
+		r.finishHiding()
+	}
+
+	if r.inactive() || r.Element == nil {
+		r.setHover(nil)
+		return
+	}
+
+	r.focus.Elements = r.focus.Elements[:0]
+	walk(r.Element, func(e Drawer) {
+		if f, ok := e.(Focusable); ok {
+			r.focus.Elements = append(r.focus.Elements, f)
+		}
+	})
+
+	mouse := geo.VecXY(geo.I2F2(ebiten.CursorPosition()))
+	moved := mouse != r.lastMouse
+	r.lastMouse = mouse
+	if moved {
+		r.setHover(r.target(mouse))
+		if r.pressed != nil {
+			r.pressed.HandleEvent(Event{Type: EventDrag, Pos: mouse})
+		}
+	}
+
+	if _, wheel := ebiten.Wheel(); wheel != 0 {
+		scroll(r.path(mouse), -wheel*WheelScrollStep)
+	}
+
+	if f := r.focus.Current(); f != r.lastFocus {
+		r.lastFocus = f
+		if f != nil {
+			scrollTo(r.Element, f)
+		}
+	}
+
+	if u, ok := r.focus.Current().(FocusUpdater); ok {
+		u.UpdateFocused()
+	}
+}
+
+// Capturing returns true if the element with focus is taking all keyboard input, in
+// which case the game should ignore keys that aren't part of the Root's KeyMap. The
+// navigation and confirm actions don't reach later keymap layers while it is.
+func (r *Root) Capturing() bool {
+	c, ok := r.focus.Current().(Capturer)
+	return ok && !r.inactive() && c.Capturing()
+}
+
+// Draw lays out and draws the tree.
+func (r *Root) Draw(dst *ebiten.Image) {
+	if r.Hidden || r.Element == nil {
+		return
+	}
+	r.rect = r.Bounds
+	if s, ok := r.Element.(Sizer); ok && !r.Fill {
+		size := s.PreferredSize()
+		r.rect = geo.RectWH(math.Min(size.X, r.Bounds.W), math.Min(size.Y, r.Bounds.H))
+		r.rect.SetTopLeft(r.Anchor.TopLeft(r.rect, r.Bounds).XY())
+	}
+	r.Element.Draw(dst, r.rect)
+}
+
+// Rect returns the rectangle that the last call to Draw put the tree in.
+func (r *Root) Rect() geo.Rect {
+	return r.rect
+}
+
+// KeyMap returns a KeyMap that handles the UI actions for this Root. It has no buttons
+// bound, the caller binds them to the actions above and adds it to its Layers.
+func (r *Root) KeyMap() *keymap.KeyMap {
+	r.navDown = map[keymap.Action]bool{}
+	btnHandlers := keymap.ButtonHandlerMap{
+		ActionClick:   r.handleClick,
+		ActionUp:      r.navHandler(ActionUp, Up),
+		ActionDown:    r.navHandler(ActionDown, Down),
+		ActionLeft:    r.navHandler(ActionLeft, Left),
+		ActionRight:   r.navHandler(ActionRight, Right),
+		ActionConfirm: r.handleConfirm,
+	}
+	axisHandlers := keymap.AxisHandlerMap{
+		ActionNavX:    r.navAxisHandler(ActionNavX, Left, Right),
+		ActionNavY:    r.navAxisHandler(ActionNavY, Up, Down),
+		ActionScrollY: r.scrollAxisHandler,
+	}
+	return keymap.New(btnHandlers, axisHandlers)
+}
+
+func (r *Root) handleClick(down bool) bool {
+	wasDown := r.clickDown
+	r.clickDown = down
+	if r.inactive() || r.Element == nil {
+		return false
+	}
+
+	mouse := geo.VecXY(geo.I2F2(ebiten.CursorPosition()))
+	path := r.path(mouse)
+	switch {
+	case down && !wasDown:
+		if len(path) == 0 {
+			return false
+		}
+		r.pressed = path[len(path)-1]
+		dispatch(path, Event{Type: EventPress, Pos: mouse})
+	case !down && wasDown && r.pressed != nil:
+		pressed := r.pressed
+		r.pressed = nil
+		pressed.HandleEvent(Event{Type: EventRelease, Pos: mouse})
+		if len(path) > 0 && path[len(path)-1] == pressed {
+			dispatch(path, Event{Type: EventClick, Pos: mouse})
+		}
+		return true
+	}
+	return len(path) > 0 || r.pressed != nil
+}
+
+func (r *Root) navHandler(action keymap.Action, dir Direction) keymap.ButtonHandler {
+	return func(down bool) bool {
+		wasDown := r.navDown[action]
+		r.navDown[action] = down
+		if r.inactive() {
+			return false
+		}
+		if down && !wasDown {
+			r.move(dir)
+		}
+		return (r.Modal || r.Capturing()) && down
+	}
+}
+
+func (r *Root) navAxisHandler(action keymap.Action, neg, pos Direction) keymap.AxisHandler {
+	return func(val float64) bool {
+		wasPushed := r.navDown[action]
+		pushed := math.Abs(val) > NavAxisThreshold
+		r.navDown[action] = pushed
+		if r.inactive() {
+			return false
+		}
+		if pushed && !wasPushed {
+			if val < 0 {
+				r.move(neg)
+			} else {
+				r.move(pos)
+			}
+		}
+		return r.Modal && pushed
+	}
+}
+
+// scrollAxisHandler scrolls whatever has focus, or if nothing does then whatever is
+// under the mouse.
+func (r *Root) scrollAxisHandler(val float64) bool {
+	if r.inactive() || r.Element == nil || math.Abs(val) < ScrollAxisThreshold {
+		return false
+	}
+	pos := r.lastMouse
+	if f := r.focus.Current(); f != nil {
+		pos = center(f.Rect())
+	}
+	return scroll(r.path(pos), val*AxisScrollSpeed) && r.Modal
+}
+
+// move lets the element with focus handle dir if it wants to, otherwise moves focus.
+func (r *Root) move(dir Direction) {
+	if h, ok := r.focus.Current().(DirectionHandler); ok && h.HandleDirection(dir) {
+		return
+	}
+	r.focus.Move(dir)
+}
+
+func (r *Root) handleConfirm(down bool) bool {
+	wasDown := r.navDown[ActionConfirm]
+	r.navDown[ActionConfirm] = down
+	if r.inactive() {
+		return false
+	}
+	if down && !wasDown {
+		r.focus.Activate()
+	}
+	// Whatever else the button does shouldn't happen while it confirms something.
+	return down && (r.Modal || r.focus.Current() != nil)
+}
+
+// inactive returns true if the Root should ignore input.
+func (r *Root) inactive() bool {
+	return r.Hidden || r.hiding
+}
+
+func (r *Root) setHover(h EventHandler) {
+	if h == r.hover {
+		return
+	}
+	if r.hover != nil {
+		r.hover.HandleEvent(Event{Type: EventLeave, Pos: r.lastMouse})
+	}
+	r.hover = h
+	if h != nil {
+		h.HandleEvent(Event{Type: EventEnter, Pos: r.lastMouse})
+		if f, ok := h.(Focusable); ok && f.CanFocus() {
+			r.focus.Set(f)
+		}
+	}
+}
+
+// target returns the top element at pos, or nil if there isn't one.
+func (r *Root) target(pos geo.Vec) EventHandler {
+	path := r.path(pos)
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// path returns the EventHandlers at pos from the outermost to the top one.
+func (r *Root) path(pos geo.Vec) []EventHandler {
+	return hitTest(r.Element, pos)
+}
+
+func hitTest(e Drawer, pos geo.Vec) []EventHandler {
+	var path []EventHandler
+	if h, ok := e.(EventHandler); ok {
+		if !h.Rect().CollidePoint(pos.XY()) {
+			return nil
+		}
+		path = append(path, h)
+	}
+	if p, ok := e.(Parent); ok {
+		children := p.Children()
+		for i := len(children) - 1; i >= 0; i-- {
+			if sub := hitTest(children[i], pos); len(sub) > 0 {
+				return append(path, sub...)
+			}
+		}
+	}
+	return path
+}
+
+// dispatch sends e to the last element in path and then its ancestors until one handles
+// it.
+func dispatch(path []EventHandler, e Event) {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].HandleEvent(e) {
+			return
+		}
+	}
+}
+
+// scroll scrolls the innermost Scroller in path that can move by delta.
+func scroll(path []EventHandler, delta float64) bool {
+	for i := len(path) - 1; i >= 0; i-- {
+		if s, ok := path[i].(Scroller); ok && s.Scroll(delta) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrollTo makes every Scroller that contains target, starting with the innermost,
+// scroll it into view. It returns false if target isn't in the tree below e.
+func scrollTo(e Drawer, target Focusable) bool {
+	if f, ok := e.(Focusable); ok && f == target {
+		return true
+	}
+	p, ok := e.(Parent)
+	if !ok {
+		return false
+	}
+	for _, c := range p.Children() {
+		if scrollTo(c, target) {
+			if s, ok := e.(Scroller); ok {
+				s.ScrollTo(target.Rect())
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for e and every element in the tree below it, in drawing order.
+func walk(e Drawer, fn func(e Drawer)) {
+	fn(e)
+	if p, ok := e.(Parent); ok {
+		for _, c := range p.Children() {
+			walk(c, fn)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"image"
+	"math"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// ScrollContainer is a container that shows part of an element that is taller than the
+// space it has, and scrolls it vertically. Only the part within its bounds is drawn and
+// can be clicked.
+//
+// It scrolls with the mouse wheel while the mouse is over it, by dragging its scrollbar,
+// with the ActionScrollY axis while it or something in it has focus, and automatically to
+// show the element that gets focus.
+type ScrollContainer struct {
+	Element WeightedDrawer
+	// Height is the preferred height. 0 means the Element's preferred height, in which
+	// case it only scrolls if it's drawn in less space than that.
+	Height float64
+	// Offset is how far the Element is scrolled from the top.
+	Offset float64
+	// Bar and Thumb are the scrollbar's track and the part that shows what is visible.
+	// The scrollbar is only drawn, and only takes up BarWidth on the right side, when
+	// there is something to scroll.
+	Bar      Drawer
+	Thumb    Drawer
+	BarWidth float64
+	Wt       float64
+
+	lastRect   geo.Rect
+	bar        geo.Rect
+	thumb      geo.Rect
+	contentH   float64
+	buf        *ebiten.Image
+	dragging   bool
+	dragOffset float64
+}
+
+// Scroll moves the Element up by delta, or down if it's negative. It returns false if
+// it was already as far as it can go in that direction.
+func (s *ScrollContainer) Scroll(delta float64) bool {
+	old := s.Offset
+	s.Offset = s.clamp(s.Offset + delta)
+	return s.Offset != old
+}
+
+// ScrollTo scrolls as little as possible to make r, which is where something in the
+// Element was last drawn, visible. If r doesn't fit then its top is shown.
+func (s *ScrollContainer) ScrollTo(r geo.Rect) {
+	switch {
+	case r.Top() < s.lastRect.Top() || r.H > s.lastRect.H:
+		s.Offset -= s.lastRect.Top() - r.Top()
+	case r.Bottom() > s.lastRect.Bottom():
+		s.Offset += r.Bottom() - s.lastRect.Bottom()
+	}
+	s.Offset = s.clamp(s.Offset)
+}
+
+func (s *ScrollContainer) clamp(offset float64) float64 {
+	return math.Max(0, math.Min(s.contentH-s.lastRect.H, offset))
+}
+
+// HandleEvent drags the scrollbar. Pressing the bar outside of the thumb jumps there.
+func (s *ScrollContainer) HandleEvent(e Event) bool {
+	switch e.Type {
+	case EventPress:
+		if !s.bar.CollidePoint(e.Pos.XY()) {
+			return false
+		}
+		s.dragging = true
+		s.dragOffset = e.Pos.Y - s.thumb.Y
+		if !s.thumb.CollidePoint(e.Pos.XY()) {
+			s.dragOffset = s.thumb.H / 2
+			s.dragTo(e.Pos.Y)
+		}
+	case EventDrag:
+		if s.dragging {
+			s.dragTo(e.Pos.Y)
+		}
+	case EventRelease:
+		s.dragging = false
+	}
+	return true
+}
+
+// dragTo scrolls so that the thumb is where it's being dragged to.
+func (s *ScrollContainer) dragTo(y float64) {
+	travel := s.bar.H - s.thumb.H
+	if travel <= 0 {
+		return
+	}
+	t := (y - s.dragOffset - s.bar.Y) / travel
+	s.Offset = s.clamp(t * (s.contentH - s.lastRect.H))
+}
+
+// Rect returns the rectangle that the last call to Draw put the ScrollContainer at.
+func (s *ScrollContainer) Rect() geo.Rect {
+	return s.lastRect
+}
+
+// Draw draws the visible part of the Element, and the scrollbar, to the image within
+// bounds.
+func (s *ScrollContainer) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	s.lastRect = bounds
+	if s.Element == nil {
+		return
+	}
+
+	width := bounds.W
+	s.contentH = preferredSize(s.Element).Y
+	scrollable := s.contentH > bounds.H
+	if scrollable {
+		width = math.Max(width-s.BarWidth, 0)
+	}
+	s.contentH = math.Max(s.contentH, bounds.H)
+	s.Offset = s.clamp(s.Offset)
+
+	// The Element is drawn to a buffer the size of dst, so that it's still positioned in
+	// the same coordinates as everything else, and only the visible part is copied.
+	buf, err := fitBuffer(s.buf, dst)
+	if err != nil {
+		return
+	}
+	s.buf = buf
+	s.buf.Clear()
+	s.Element.Draw(s.buf, geo.RectXYWH(bounds.X, bounds.Y-s.Offset, width, s.contentH))
+	view := image.Rect(int(bounds.X), int(bounds.Y), int(bounds.X+width), int(bounds.Bottom())).Intersect(dst.Bounds())
+	drawRegion(dst, s.buf, view, geo.RectXYWH(float64(view.Min.X), float64(view.Min.Y),
+		float64(view.Dx()), float64(view.Dy())))
+
+	s.bar, s.thumb = geo.Rect{}, geo.Rect{}
+	if !scrollable {
+		return
+	}
+	s.bar = geo.RectXYWH(bounds.Right()-s.BarWidth, bounds.Y, s.BarWidth, bounds.H)
+	s.thumb = geo.RectXYWH(s.bar.X, 0, s.bar.W, s.bar.H*bounds.H/s.contentH)
+	s.thumb.Y = s.bar.Y + (s.bar.H-s.thumb.H)*s.Offset/(s.contentH-bounds.H)
+	if s.Bar != nil {
+		s.Bar.Draw(dst, s.bar)
+	}
+	if s.Thumb != nil {
+		s.Thumb.Draw(dst, s.thumb)
+	}
+}
+
+// MinSize returns the Element's minimum width plus BarWidth, by no height since it can
+// scroll.
+func (s *ScrollContainer) MinSize() geo.Vec {
+	if s.Element == nil {
+		return geo.Vec0
+	}
+	return geo.VecXY(minSize(s.Element).X+s.BarWidth, 0)
+}
+
+// PreferredSize returns the Element's preferred width, plus BarWidth if Height is too
+// small to show all of it, by Height.
+func (s *ScrollContainer) PreferredSize() geo.Vec {
+	if s.Element == nil {
+		return geo.VecXY(0, s.Height)
+	}
+	size := preferredSize(s.Element)
+	if s.Height > 0 && s.Height < size.Y {
+		size = geo.VecXY(size.X+s.BarWidth, s.Height)
+	}
+	return size
+}
+
+// Children returns the ScrollContainer's sub-element.
+func (s *ScrollContainer) Children() []WeightedDrawer {
+	if s.Element == nil {
+		return nil
+	}
+	return []WeightedDrawer{s.Element}
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (s *ScrollContainer) Weight() float64 {
+	return s.Wt
+}
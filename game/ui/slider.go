@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Slider is an element for choosing a number between Min and Max. The value is set by
+// clicking or dragging along the track with the mouse, or stepped with left and right
+// while the Slider has focus.
+//
+// The Track is drawn to fill the Slider's bounds and the Thumb is drawn at ThumbSize
+// where the value is along it. FocusedThumb replaces Thumb while the Slider has focus or
+// the mouse is over it, if it's set.
+type Slider struct {
+	Value float64
+	Min   float64
+	Max   float64
+	// Step is how much the value changes with each press of left or right. 0 means a
+	// tenth of the range. The value is not rounded to Steps when set with the mouse.
+	Step         float64
+	OnChange     func(value float64)
+	Track        Drawer
+	Thumb        Drawer
+	FocusedThumb Drawer
+	ThumbSize    geo.Vec
+	// Size is the Slider's preferred size. It is never smaller than ThumbSize.
+	Size     geo.Vec
+	Wt       float64
+	Disabled bool
+	Hover    bool
+	Focused  bool
+	lastRect geo.Rect
+}
+
+// HandleEvent sets the value from the mouse position when the Slider is pressed or
+// dragged.
+func (s *Slider) HandleEvent(e Event) bool {
+	if s.Disabled {
+		s.Hover = false
+		return true
+	}
+	switch e.Type {
+	case EventEnter:
+		s.Hover = true
+	case EventLeave:
+		s.Hover = false
+	case EventPress, EventDrag:
+		s.setFromX(e.Pos.X)
+	}
+	return true
+}
+
+// HandleDirection steps the value with left and right.
+func (s *Slider) HandleDirection(dir Direction) bool {
+	switch dir {
+	case Left:
+		s.SetValue(s.Value - s.step())
+	case Right:
+		s.SetValue(s.Value + s.step())
+	default:
+		return false
+	}
+	return true
+}
+
+// SetValue sets the value, clamped between Min and Max, and calls OnChange if it changed.
+func (s *Slider) SetValue(v float64) {
+	v = math.Max(s.Min, math.Min(s.Max, v))
+	if v == s.Value {
+		return
+	}
+	s.Value = v
+	if s.OnChange != nil {
+		s.OnChange(v)
+	}
+}
+
+func (s *Slider) step() float64 {
+	if s.Step > 0 {
+		return s.Step
+	}
+	return (s.Max - s.Min) / 10
+}
+
+// setFromX sets the value from a position along the track.
+func (s *Slider) setFromX(x float64) {
+	travel := s.lastRect.W - s.ThumbSize.X
+	if travel <= 0 {
+		return
+	}
+	t := (x - s.lastRect.X - s.ThumbSize.X/2) / travel
+	s.SetValue(s.Min + t*(s.Max-s.Min))
+}
+
+// SetFocus gives or takes away focus.
+func (s *Slider) SetFocus(focused bool) {
+	s.Focused = focused
+	if !focused {
+		s.Hover = false
+	}
+}
+
+// CanFocus returns true unless the Slider is Disabled.
+func (s *Slider) CanFocus() bool {
+	return !s.Disabled
+}
+
+// Rect returns the rectangle that the last call to Draw put the Slider at.
+func (s *Slider) Rect() geo.Rect {
+	return s.lastRect
+}
+
+// Activate does nothing, the value is only changed with directions or the mouse.
+func (s *Slider) Activate() {}
+
+// Draw draws the Slider to the image within bounds.
+func (s *Slider) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	s.lastRect = bounds
+	if s.Track != nil {
+		s.Track.Draw(dst, bounds)
+	}
+
+	t := 0.0
+	if s.Max > s.Min {
+		t = (s.Value - s.Min) / (s.Max - s.Min)
+	}
+	thumb := geo.RectWH(s.ThumbSize.XY())
+	thumb.SetTopLeft(bounds.X+t*(bounds.W-thumb.W), bounds.Y+(bounds.H-thumb.H)/2)
+	if (s.Focused || s.Hover) && s.FocusedThumb != nil {
+		s.FocusedThumb.Draw(dst, thumb)
+	} else if s.Thumb != nil {
+		s.Thumb.Draw(dst, thumb)
+	}
+}
+
+// MinSize returns ThumbSize.
+func (s *Slider) MinSize() geo.Vec {
+	return s.ThumbSize
+}
+
+// PreferredSize returns Size, grown to fit the thumb if needed.
+func (s *Slider) PreferredSize() geo.Vec {
+	return geo.VecXY(math.Max(s.Size.X, s.ThumbSize.X), math.Max(s.Size.Y, s.ThumbSize.Y))
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (s *Slider) Weight() float64 {
+	return s.Wt
+}
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"image/color"
+	"math"
+	"unicode"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"golang.org/x/image/font"
+)
+
+const (
+	// keyRepeatDelay is how many frames a key is held before it starts repeating.
+	keyRepeatDelay = 30
+	// keyRepeatInterval is how many frames there are between repeats of a held key.
+	keyRepeatInterval = 3
+	// cursorBlink is how many frames the cursor is shown, and then hidden, for.
+	cursorBlink = 30
+)
+
+// TextInput is an element for typing a line of text. Clicking or activating it starts
+// editing, after which typed characters are inserted at the cursor. Characters come from
+// ebiten.InputChars so they respect the keyboard layout and input methods. Activating it
+// again, e.g. with confirm, ends editing and calls OnSubmit; losing focus ends editing
+// without it.
+//
+// While editing, left and right move the cursor, and Backspace, Delete, Home and End work
+// as usual. The Root it's in reports Capturing so that the game can ignore the keys that
+// are being typed.
+type TextInput struct {
+	Text string
+	// MaxLen is the most characters the Text can have, 0 means there is no limit.
+	MaxLen   int
+	OnChange func(text string)
+	OnSubmit func(text string)
+	Face     font.Face
+	Color    color.Color
+	// Background is drawn behind the text. EditingBackground replaces it while editing,
+	// and FocusedBackground while it has focus or the mouse is over it, if they're set.
+	Background        Drawer
+	FocusedBackground Drawer
+	EditingBackground Drawer
+	Padding           Insets
+	// Width is the preferred width of the area the text is typed in.
+	Width    float64
+	Wt       float64
+	Disabled bool
+	Hover    bool
+	Focused  bool
+	Editing  bool
+	// Cursor is the index of the character that typing inserts before.
+	Cursor   int
+	keys     map[ebiten.Key]int
+	frame    int
+	lastRect geo.Rect
+}
+
+// HandleEvent starts editing when the TextInput is clicked.
+func (t *TextInput) HandleEvent(e Event) bool {
+	if t.Disabled {
+		t.Hover = false
+		return true
+	}
+	switch e.Type {
+	case EventEnter:
+		t.Hover = true
+	case EventLeave:
+		t.Hover = false
+	case EventClick:
+		if !t.Editing {
+			t.startEditing()
+		}
+	}
+	return true
+}
+
+// HandleDirection moves the cursor with left and right while editing. Up and down do
+// nothing while editing so that focus stays put.
+func (t *TextInput) HandleDirection(dir Direction) bool {
+	if !t.Editing {
+		return false
+	}
+	switch dir {
+	case Left:
+		t.setCursor(t.Cursor - 1)
+	case Right:
+		t.setCursor(t.Cursor + 1)
+	}
+	return true
+}
+
+// UpdateFocused handles typing while editing.
+func (t *TextInput) UpdateFocused() {
+	t.frame++
+	if !t.Editing {
+		return
+	}
+	text := []rune(t.Text)
+	// Keep the cursor within the Text in case it was changed from outside.
+	t.setCursor(t.Cursor)
+	cursor := t.Cursor
+	changed := false
+
+	for _, r := range ebiten.InputChars() {
+		if !unicode.IsPrint(r) || (t.MaxLen > 0 && len(text) >= t.MaxLen) {
+			continue
+		}
+		text = append(text[:t.Cursor], append([]rune{r}, text[t.Cursor:]...)...)
+		t.Cursor++
+		changed = true
+	}
+
+	if t.repeated(ebiten.KeyBackspace) && t.Cursor > 0 {
+		text = append(text[:t.Cursor-1], text[t.Cursor:]...)
+		t.Cursor--
+		changed = true
+	}
+	if t.repeated(ebiten.KeyDelete) && t.Cursor < len(text) {
+		text = append(text[:t.Cursor], text[t.Cursor+1:]...)
+		changed = true
+	}
+	if t.repeated(ebiten.KeyHome) {
+		t.Cursor = 0
+	}
+	if t.repeated(ebiten.KeyEnd) {
+		t.Cursor = len(text)
+	}
+
+	// Show the cursor right away when it moves rather than part way through a blink.
+	if changed || t.Cursor != cursor {
+		t.frame = 0
+	}
+	if changed {
+		t.Text = string(text)
+		if t.OnChange != nil {
+			t.OnChange(t.Text)
+		}
+	}
+}
+
+// Capturing returns true while editing.
+func (t *TextInput) Capturing() bool {
+	return t.Editing
+}
+
+// repeated returns true on the frame that key is pressed, and then repeatedly while it's
+// held.
+func (t *TextInput) repeated(key ebiten.Key) bool {
+	if t.keys == nil {
+		t.keys = map[ebiten.Key]int{}
+	}
+	if !ebiten.IsKeyPressed(key) {
+		t.keys[key] = 0
+		return false
+	}
+	t.keys[key]++
+	held := t.keys[key]
+	return held == 1 || (held >= keyRepeatDelay && (held-keyRepeatDelay)%keyRepeatInterval == 0)
+}
+
+// setCursor moves the cursor to i, kept within the Text, and restarts the blink if it
+// moved.
+func (t *TextInput) setCursor(i int) {
+	i = int(math.Max(0, math.Min(float64(len([]rune(t.Text))), float64(i))))
+	if i != t.Cursor {
+		t.Cursor = i
+		t.frame = 0
+	}
+}
+
+func (t *TextInput) startEditing() {
+	t.Editing = true
+	t.setCursor(len([]rune(t.Text)))
+	t.frame = 0
+	// Keys that are already held, e.g. from clicking, shouldn't count as pressed.
+	t.keys = map[ebiten.Key]int{}
+	for _, k := range []ebiten.Key{ebiten.KeyBackspace, ebiten.KeyDelete, ebiten.KeyHome, ebiten.KeyEnd} {
+		if ebiten.IsKeyPressed(k) {
+			t.keys[k] = 1
+		}
+	}
+}
+
+// SetFocus gives or takes away focus. Losing focus ends editing.
+func (t *TextInput) SetFocus(focused bool) {
+	t.Focused = focused
+	if !focused {
+		t.Hover = false
+		t.Editing = false
+	}
+}
+
+// CanFocus returns true unless the TextInput is Disabled.
+func (t *TextInput) CanFocus() bool {
+	return !t.Disabled
+}
+
+// Rect returns the rectangle that the last call to Draw put the TextInput at.
+func (t *TextInput) Rect() geo.Rect {
+	return t.lastRect
+}
+
+// Activate starts editing, or if already editing it ends editing and calls OnSubmit.
+func (t *TextInput) Activate() {
+	if t.Disabled {
+		return
+	}
+	if !t.Editing {
+		t.startEditing()
+		return
+	}
+	t.Editing = false
+	if t.OnSubmit != nil {
+		t.OnSubmit(t.Text)
+	}
+}
+
+// Draw draws the TextInput to the image within bounds. If the text doesn't fit then the
+// part around the cursor is shown.
+func (t *TextInput) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	t.lastRect = bounds
+	if bg := t.background(); bg != nil {
+		bg.Draw(dst, bounds)
+	}
+
+	inner := t.Padding.Shrink(bounds)
+	text := []rune(t.Text)
+	cursor := int(math.Min(float64(t.Cursor), float64(len(text))))
+	label := &Text{Face: t.Face, Color: t.Color, Anchor: AnchorLeft}
+
+	// Scroll so that the cursor is visible, then show as much after it as fits.
+	start := 0
+	for start < cursor && label.width(string(text[start:cursor])) > inner.W {
+		start++
+	}
+	end := len(text)
+	for end > cursor && label.width(string(text[start:end])) > inner.W {
+		end--
+	}
+	label.Text = string(text[start:end])
+	label.Draw(dst, inner)
+
+	if t.Editing && (t.frame/cursorBlink)%2 == 0 {
+		h := label.lineHeight()
+		x := inner.X + label.width(string(text[start:cursor]))
+		ebitenutil.DrawRect(dst, x, inner.Y+(inner.H-h)/2, 1, h, t.Color)
+	}
+}
+
+func (t *TextInput) background() Drawer {
+	switch {
+	case t.Editing && t.EditingBackground != nil:
+		return t.EditingBackground
+	case (t.Editing || t.Focused || t.Hover) && t.FocusedBackground != nil:
+		return t.FocusedBackground
+	}
+	return t.Background
+}
+
+// MinSize returns the height of one line of text, plus Padding.
+func (t *TextInput) MinSize() geo.Vec {
+	label := Text{Face: t.Face}
+	return t.Padding.Grow(geo.VecXY(0, label.lineHeight()))
+}
+
+// PreferredSize returns Width by the height of one line of text, plus Padding.
+func (t *TextInput) PreferredSize() geo.Vec {
+	label := Text{Face: t.Face}
+	return t.Padding.Grow(geo.VecXY(t.Width, label.lineHeight()))
+}
+
+// Weight returns the relative weight for allocating space within a container.
+func (t *TextInput) Weight() float64 {
+	return t.Wt
+}
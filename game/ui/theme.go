@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// missingColor is returned for colors that a Theme doesn't have, so that they stand out.
+var missingColor = color.NRGBA{0xff, 0, 0xff, 0xff}
+
+// Theme holds the look of a ui so that it can be changed in one place, and switched while
+// the game is running by building Defs again with a different Theme. It is usually decoded
+// from a JSON file with ParseTheme.
+//
+// Styles are ElementDef properties by style name, e.g. the fonts, colors, paddings and
+// button backgrounds of a kind of element. They're used by the elements of any Def built
+// with the Theme, unless the Def has its own style with the same name. Colors are named
+// colors in the same format as ElementDef.Color. Defs refer to them as "@<name>", e.g.
+// "@text", and the game can use them with Color.
+type Theme struct {
+	Name   string                     `json:"name"`
+	Colors map[string]string          `json:"colors"`
+	Styles map[string]json.RawMessage `json:"styles"`
+	colors map[string]color.NRGBA
+}
+
+// ParseTheme decodes a JSON Theme.
+func ParseTheme(data []byte) (*Theme, error) {
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	t.colors = map[string]color.NRGBA{}
+	for name, c := range t.Colors {
+		if strings.HasPrefix(c, "@") {
+			return nil, fmt.Errorf("color '%s': can't refer to another color", name)
+		}
+		parsed, err := parseColor(c)
+		if err != nil {
+			return nil, fmt.Errorf("color '%s': %v", name, err)
+		}
+		t.colors[name] = parsed
+	}
+	return &t, nil
+}
+
+// Color returns the named color, or magenta if the Theme doesn't have it or is nil.
+func (t *Theme) Color(name string) color.Color {
+	if t == nil {
+		return missingColor
+	}
+	if c, ok := t.colors[name]; ok {
+		return c
+	}
+	return missingColor
+}
+
+// style returns the named style's properties.
+func (t *Theme) style(name string) (json.RawMessage, bool) {
+	if t == nil {
+		return nil, false
+	}
+	s, ok := t.Styles[name]
+	return s, ok
+}
+
+// color returns the named color for a reference in a Def.
+func (t *Theme) color(name string) (color.NRGBA, error) {
+	if t == nil {
+		return color.NRGBA{}, fmt.Errorf("no theme for color '@%s'", name)
+	}
+	c, ok := t.colors[name]
+	if !ok {
+		return color.NRGBA{}, fmt.Errorf("theme '%s' has no color '%s'", t.Name, name)
+	}
+	return c, nil
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/Bredgren/geo"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Transform is a container that moves, scales, fades and tints its sub-element, e.g. for
+// animating it with the tween package. It takes up the same space as the Element would,
+// and is only Weighted as much as the Element is.
+//
+// Offset moves where the Element is laid out, so it is also clicked there. Scale, Alpha
+// and Tint only change how it looks.
+type Transform struct {
+	Element WeightedDrawer
+	Offset  geo.Vec
+	// Scale is relative to the center of the bounds given to Draw.
+	Scale geo.Vec
+	// Alpha is the opacity from 0 to 1.
+	Alpha float64
+	// Tint is multiplied with the Element's colors. It's ignored if nil.
+	Tint color.Color
+	buf  *ebiten.Image
+}
+
+// NewTransform returns a Transform that doesn't change e until its fields are changed.
+func NewTransform(e WeightedDrawer) *Transform {
+	return &Transform{
+		Element: e,
+		Scale:   geo.VecXY(1, 1),
+		Alpha:   1,
+	}
+}
+
+// Draw draws the Element to the image within bounds moved by Offset.
+func (t *Transform) Draw(dst *ebiten.Image, bounds geo.Rect) {
+	if t.Element == nil || t.Alpha <= 0 || t.Scale.X == 0 || t.Scale.Y == 0 {
+		return
+	}
+	bounds.X += t.Offset.X
+	bounds.Y += t.Offset.Y
+	if t.Scale == geo.VecXY(1, 1) && t.Alpha >= 1 && t.Tint == nil {
+		t.Element.Draw(dst, bounds)
+		return
+	}
+
+	// Draw off screen, in the same coordinates so that the Element's rects are still
+	// right, then change how that looks.
+	buf, err := fitBuffer(t.buf, dst)
+	if err != nil {
+		return
+	}
+	t.buf = buf
+	t.buf.Clear()
+	t.Element.Draw(t.buf, bounds)
+
+	cx, cy := bounds.X+bounds.W/2, bounds.Y+bounds.H/2
+	var opts ebiten.DrawImageOptions
+	opts.GeoM.Translate(-cx, -cy)
+	opts.GeoM.Scale(t.Scale.XY())
+	opts.GeoM.Translate(cx, cy)
+	r, g, b := 1.0, 1.0, 1.0
+	if t.Tint != nil {
+		c := color.NRGBAModel.Convert(t.Tint).(color.NRGBA)
+		r, g, b = float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff
+	}
+	opts.ColorM.Scale(r, g, b, t.Alpha)
+	dst.DrawImage(t.buf, &opts)
+}
+
+// MinSize returns the Element's minimum size.
+func (t *Transform) MinSize() geo.Vec {
+	if t.Element == nil {
+		return geo.Vec0
+	}
+	return minSize(t.Element)
+}
+
+// PreferredSize returns the Element's preferred size.
+func (t *Transform) PreferredSize() geo.Vec {
+	if t.Element == nil {
+		return geo.Vec0
+	}
+	return preferredSize(t.Element)
+}
+
+// Children returns the Transform's sub-element.
+func (t *Transform) Children() []WeightedDrawer {
+	if t.Element == nil {
+		return nil
+	}
+	return []WeightedDrawer{t.Element}
+}
+
+// Weight returns the Element's weight.
+func (t *Transform) Weight() float64 {
+	if t.Element == nil {
+		return 0
+	}
+	return t.Element.Weight()
+}
@@ -0,0 +1,15 @@
+package util
+
+import "strings"
+
+// ErrorList is a list of problems that were found together, e.g. all of the mistakes in
+// a PSD's layer structure or the assets that failed to load.
+type ErrorList []error
+
+func (e ErrorList) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
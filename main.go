@@ -1,9 +1,11 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/Bredgren/game1/game"
+	"github.com/Bredgren/game1/game/asset"
 	"github.com/hajimehoshi/ebiten"
 )
 
@@ -12,6 +14,12 @@ const (
 	screenHeight = 400
 )
 
+var assetDir = flag.String("assets", "", "Read assets from this directory (e.g. game/asset/assets) "+
+	"instead of the embedded ones and reload them when they change")
+
+var settingsFile = flag.String("settings", "settings.json", "Load the player's settings from this file "+
+	"and save them to it when they change")
+
 var theGame *game.Game
 
 func update(screen *ebiten.Image) error {
@@ -26,12 +34,15 @@ func update(screen *ebiten.Image) error {
 	return nil
 }
 
-func init() {
-	theGame = game.New(screenWidth, screenHeight)
-}
-
 func main() {
-	if err := ebiten.Run(update, screenWidth, screenHeight, 2, "Game Title"); err != nil {
+	flag.Parse()
+	if *assetDir != "" {
+		asset.UseDir(*assetDir)
+	}
+
+	theGame = game.New(screenWidth, screenHeight, *settingsFile)
+
+	if err := ebiten.Run(update, screenWidth, screenHeight, theGame.ScreenScale(), "Game Title"); err != nil {
 		log.Fatal(err)
 	}
 }